@@ -0,0 +1,76 @@
+package timsort
+
+import (
+	"testing"
+)
+
+// intAccessor is a []int behind the Accessor interface
+type intAccessor []int
+
+func (p intAccessor) Len() int         { return len(p) }
+func (p intAccessor) Get(i int) int    { return p[i] }
+func (p intAccessor) Set(i int, v int) { p[i] = v }
+
+func benchmarkSlice(b *testing.B, size int, shape string) {
+	b.StopTimer()
+
+	for j := 0; j < b.N; j++ {
+		v := makeInts(size, shape)
+
+		b.StartTimer()
+		Slice(v, LessThanInt)
+		b.StopTimer()
+	}
+}
+
+func benchmarkSortAccessor(b *testing.B, size int, shape string) {
+	b.StopTimer()
+
+	for j := 0; j < b.N; j++ {
+		v := makeInts(size, shape)
+
+		b.StartTimer()
+		SortAccessor[int](intAccessor(v), LessThanInt)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSliceXor1K(b *testing.B) {
+	benchmarkSlice(b, 1024, "xor")
+}
+
+func BenchmarkSortAccessorXor1K(b *testing.B) {
+	benchmarkSortAccessor(b, 1024, "xor")
+}
+
+func BenchmarkSliceSorted1K(b *testing.B) {
+	benchmarkSlice(b, 1024, "sorted")
+}
+
+func BenchmarkSortAccessorSorted1K(b *testing.B) {
+	benchmarkSortAccessor(b, 1024, "sorted")
+}
+
+func BenchmarkSliceRandom1K(b *testing.B) {
+	benchmarkSlice(b, 1024, "random")
+}
+
+func BenchmarkSortAccessorRandom1K(b *testing.B) {
+	benchmarkSortAccessor(b, 1024, "random")
+}
+
+func BenchmarkSliceXor1M(b *testing.B) {
+	benchmarkSlice(b, 1024*1024, "xor")
+}
+
+func BenchmarkSortAccessorXor1M(b *testing.B) {
+	benchmarkSortAccessor(b, 1024*1024, "xor")
+}
+
+func BenchmarkSliceRandom1M(b *testing.B) {
+	benchmarkSlice(b, 1024*1024, "random")
+}
+
+func BenchmarkSortAccessorRandom1M(b *testing.B) {
+	benchmarkSortAccessor(b, 1024*1024, "random")
+}
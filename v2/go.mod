@@ -1,3 +1,3 @@
 module github.com/psilva261/timsort/v2
 
-go 1.14
+go 1.23
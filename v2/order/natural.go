@@ -0,0 +1,230 @@
+package order
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalString returns the natural ("human") order of strings, in which
+// runs of decimal digits compare by their numeric value, so that "img2.png"
+// sorts before "img10.png".  Everything else compares byte by byte.  Numbers
+// that differ only in leading zeros are ordered by the number of zeros, but
+// only if the strings are otherwise equal.
+func NaturalString() Order[string] {
+	return func(a, b string) int {
+		return natural(a, b, false)
+	}
+}
+
+// NaturalStringFold is like NaturalString, except that letters are compared
+// under Unicode simple case folding: letters that strings.EqualFold treats as
+// equal, such as "ς", "σ" and "Σ", compare equal, and otherwise letters
+// compare by their lower case.
+func NaturalStringFold() Order[string] {
+	return func(a, b string) int {
+		return natural(a, b, true)
+	}
+}
+
+// NaturalBytes is like NaturalString, but for byte slices.
+func NaturalBytes() Order[[]byte] {
+	return func(a, b []byte) int {
+		return natural(a, b, false)
+	}
+}
+
+// NaturalBytesFold is like NaturalStringFold, but for byte slices.
+func NaturalBytesFold() Order[[]byte] {
+	return func(a, b []byte) int {
+		return natural(a, b, true)
+	}
+}
+
+// Version returns the order of semver-like version strings such as
+// "v1.2.10" or "1.0.0-rc.1+build.5".  An optional leading "v" and build
+// metadata after "+" are ignored.  Dotted release components are compared
+// in natural order, with missing components counting as "0".  A version
+// with a pre-release tag sorts before the same version without one, and
+// pre-release tags are compared as in semver: numeric identifiers
+// numerically and before alphanumeric ones, which compare lexically.
+func Version() Order[string] {
+	return compareVersions
+}
+
+// NaturalStringSlice attaches the methods of sort.Interface to []string,
+// sorting in natural order.  It can be passed to timsort.TimSort.
+type NaturalStringSlice []string
+
+func (s NaturalStringSlice) Len() int {
+	return len(s)
+}
+
+func (s NaturalStringSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s NaturalStringSlice) Less(i, j int) bool {
+	return natural(s[i], s[j], false) < 0
+}
+
+func natural[S string | []byte](a, b S, fold bool) int {
+	tie := 0 // Leading zeros decide only if all else is equal
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			// Skip leading zeros and find the ends of both digit runs
+			zi, zj := i, j
+			for zi < len(a) && a[zi] == '0' {
+				zi++
+			}
+			for zj < len(b) && b[zj] == '0' {
+				zj++
+			}
+			ei, ej := zi, zj
+			for ei < len(a) && isDigit(a[ei]) {
+				ei++
+			}
+			for ej < len(b) && isDigit(b[ej]) {
+				ej++
+			}
+
+			// A longer number is a bigger one, equal lengths compare by digits
+			if c := cmp.Compare(ei-zi, ej-zj); c != 0 {
+				return c
+			}
+			for k := 0; k < ei-zi; k++ {
+				if c := cmp.Compare(a[zi+k], b[zj+k]); c != 0 {
+					return c
+				}
+			}
+			if tie == 0 {
+				tie = cmp.Compare(zi-i, zj-j)
+			}
+			i, j = ei, ej
+			continue
+		}
+
+		if fold {
+			ra, na := runeAt(a, i)
+			rb, nb := runeAt(b, j)
+			if c := cmp.Compare(foldRune(ra), foldRune(rb)); c != 0 {
+				return c
+			}
+			i += na
+			j += nb
+			continue
+		}
+
+		if c := cmp.Compare(a[i], b[j]); c != 0 {
+			return c
+		}
+		i++
+		j++
+	}
+
+	// One of them is exhausted, the shorter one sorts first
+	if c := cmp.Compare(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+	return tie
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// foldRune maps the runes that are equal under simple case folding to the
+// same rune, the lower case of the least rune among them
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return r
+	}
+
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		least = min(least, f)
+	}
+	return unicode.ToLower(least)
+}
+
+func runeAt[S string | []byte](s S, i int) (rune, int) {
+	if c := s[i]; c < utf8.RuneSelf {
+		return rune(c), 1
+	}
+	switch s := any(s).(type) {
+	case string:
+		return utf8.DecodeRuneInString(s[i:])
+	case []byte:
+		return utf8.DecodeRune(s[i:])
+	}
+	panic("unreachable")
+}
+
+func compareVersions(a, b string) int {
+	a, _, _ = strings.Cut(strings.TrimPrefix(a, "v"), "+")
+	b, _, _ = strings.Cut(strings.TrimPrefix(b, "v"), "+")
+	relA, preA, hasPreA := strings.Cut(a, "-")
+	relB, preB, hasPreB := strings.Cut(b, "-")
+
+	fieldsA := strings.Split(relA, ".")
+	fieldsB := strings.Split(relB, ".")
+	for k := 0; k < len(fieldsA) || k < len(fieldsB); k++ {
+		fa, fb := "0", "0"
+		if k < len(fieldsA) {
+			fa = fieldsA[k]
+		}
+		if k < len(fieldsB) {
+			fb = fieldsB[k]
+		}
+		if c := natural(fa, fb, false); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case !hasPreA && !hasPreB:
+		return 0
+	case !hasPreA:
+		return 1
+	case !hasPreB:
+		return -1
+	}
+
+	idsA := strings.Split(preA, ".")
+	idsB := strings.Split(preB, ".")
+	for k := 0; k < len(idsA) && k < len(idsB); k++ {
+		numA, numB := isNumeric(idsA[k]), isNumeric(idsB[k])
+		var c int
+		switch {
+		case numA && numB:
+			c = natural(idsA[k], idsB[k], false)
+		case numA:
+			c = -1
+		case numB:
+			c = 1
+		default:
+			c = strings.Compare(idsA[k], idsB[k])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(idsA), len(idsB))
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
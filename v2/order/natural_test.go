@@ -0,0 +1,112 @@
+package order
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/psilva261/timsort/v2"
+)
+
+func TestNaturalString(t *testing.T) {
+	a := []string{"img12.png", "img10.png", "IMG2.png", "img2.png", "img1.png", "img02.png", "img", "img2", "x", "1", "01", "001", ""}
+
+	timsort.Slice(a, NaturalString().Less)
+
+	want := []string{"", "1", "01", "001", "IMG2.png", "img", "img1.png", "img2", "img2.png", "img02.png", "img10.png", "img12.png", "x"}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("got  %q\nwant %q", a, want)
+	}
+}
+
+func TestNaturalStringFold(t *testing.T) {
+	a := []string{"b10", "B9", "a", "Ä1", "ä0", "A"}
+
+	timsort.Slice(a, NaturalStringFold().Less)
+
+	want := []string{"a", "A", "B9", "b10", "ä0", "Ä1"}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("got  %q\nwant %q", a, want)
+	}
+
+	// Letters that are equal under case folding compare equal
+	fold := NaturalStringFold()
+	for _, p := range [][2]string{
+		{"ς", "σ"}, {"σ", "Σ"}, {"ς", "Σ"},
+		{"ſ", "s"}, {"ſ", "S"}, {"\u212a", "k"},
+		{"x1ς", "X1Σ"}, {"ſ10", "s10"},
+	} {
+		if c := fold(p[0], p[1]); c != 0 || !strings.EqualFold(p[0], p[1]) {
+			t.Errorf("%q vs %q: got %d", p[0], p[1], c)
+		}
+	}
+	if c := fold("ſ2", "s10"); c >= 0 {
+		t.Errorf("ſ2 vs s10: got %d", c)
+	}
+}
+
+func TestNaturalBytes(t *testing.T) {
+	a := [][]byte{[]byte("z10"), []byte("Z9"), []byte("z9")}
+
+	timsort.Slice(a, NaturalBytes().Less)
+	if string(a[0]) != "Z9" || string(a[1]) != "z9" || string(a[2]) != "z10" {
+		t.Errorf("got %q", a)
+	}
+
+	timsort.Slice(a, NaturalBytesFold().Less)
+	if string(a[0]) != "Z9" || string(a[1]) != "z9" || string(a[2]) != "z10" {
+		t.Errorf("fold: got %q", a)
+	}
+}
+
+func TestNaturalStringSlice(t *testing.T) {
+	a := []string{"file10", "file9", "file1"}
+
+	timsort.TimSort(NaturalStringSlice(a))
+	if !reflect.DeepEqual(a, []string{"file1", "file9", "file10"}) {
+		t.Errorf("got %q", a)
+	}
+
+	b := []interface{}{"file10", "file9", "file1"}
+	less := NaturalString().LessThan()
+	timsort.Sort(b, less)
+	if !reflect.DeepEqual(b, []interface{}{"file1", "file9", "file10"}) {
+		t.Errorf("got %q", b)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	a := []string{"1.10.0", "v1.2.0", "1.2.0-rc.1", "1.2.0-beta.11", "1.2.0-beta.2", "1.2.0-alpha", "1.2.0-alpha.1", "1.2.0-alpha.beta", "1.2", "0.9.9+build.7", "2"}
+
+	timsort.Slice(a, Version().Less)
+
+	want := []string{"0.9.9+build.7", "1.2.0-alpha", "1.2.0-alpha.1", "1.2.0-alpha.beta", "1.2.0-beta.2", "1.2.0-beta.11", "1.2.0-rc.1", "v1.2.0", "1.2", "1.10.0", "2"}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("got  %q\nwant %q", a, want)
+	}
+}
+
+func makeNaturalStrings(size int) []string {
+	parts := []string{"a", "B", "b", "0", "00", "1", "01", "9", "10", "-", ".", "é", "É"}
+	a := make([]string, size)
+
+	for i := range a {
+		n := rand.Intn(4)
+		for k := 0; k < n; k++ {
+			a[i] += parts[rand.Intn(len(parts))]
+		}
+	}
+
+	return a
+}
+
+func TestNaturalStrictWeakOrder(t *testing.T) {
+	a := makeNaturalStrings(60)
+
+	checkStrictWeakOrder(t, a, NaturalString().Less)
+	checkStrictWeakOrder(t, a, NaturalStringFold().Less)
+
+	versions := []string{"1", "1.0", "1.0.0", "v1.0.1", "1.0.0-1", "1.0.0-a", "1.0.0-a.1", "1.0.0-1.a", "1.0.0-01", "1.10", "1.9", "1.09", "2.0.0-rc+b"}
+	checkStrictWeakOrder(t, versions, Version().Less)
+}
@@ -0,0 +1,117 @@
+// Package order provides composable comparators for use with timsort.
+//
+// An Order is a three-way comparison function.  Orders are built from key
+// functions with By, combined with ThenBy and inverted with Reverse, and
+// then handed to the sorting functions through one of the adapters:
+//
+//	byName := order.By(func(p Person) string { return p.Name })
+//	byAge := order.By(func(p Person) int { return p.Age })
+//
+//	timsort.Slice(people, byName.ThenBy(byAge.Reverse()).Less)
+//
+// Every Order built by this package is a strict weak ordering as long as
+// the key functions are deterministic, which is what stable sorting relies
+// on.
+package order
+
+import "cmp"
+
+// Order compares a and b and returns a negative number when a sorts before
+// b, a positive number when a sorts after b and zero when they are equal.
+type Order[T any] func(a, b T) int
+
+// Natural returns the natural order of an ordered type.  NaNs sort before
+// all other floating-point values.
+func Natural[T cmp.Ordered]() Order[T] {
+	return cmp.Compare[T]
+}
+
+// By returns an Order that compares elements by the key extracted from
+// each of them.
+func By[T any, K cmp.Ordered](key func(T) K) Order[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Rank returns an Order that compares elements by the position of their key
+// in ranks.  Keys missing from ranks sort after all listed keys and are
+// equal to each other.
+func Rank[T any, K comparable](key func(T) K, ranks ...K) Order[T] {
+	pos := make(map[K]int, len(ranks))
+	for i, k := range ranks {
+		if _, ok := pos[k]; !ok {
+			pos[k] = i
+		}
+	}
+
+	rank := func(v T) int {
+		if i, ok := pos[key(v)]; ok {
+			return i
+		}
+		return len(ranks)
+	}
+
+	return func(a, b T) int {
+		return cmp.Compare(rank(a), rank(b))
+	}
+}
+
+// ThenBy returns an Order that compares by o and breaks ties using next.
+func (o Order[T]) ThenBy(next Order[T]) Order[T] {
+	return func(a, b T) int {
+		if c := o(a, b); c != 0 {
+			return c
+		}
+		return next(a, b)
+	}
+}
+
+// Reverse returns the reverse of o.  Equal elements stay equal, so a
+// stable sort keeps them in their original order.
+func (o Order[T]) Reverse() Order[T] {
+	return func(a, b T) int {
+		return o(b, a)
+	}
+}
+
+// Less reports whether a sorts before b.  Its method value can be passed to
+// timsort.Slice, and for an Order[int] to timsort.Ints.
+func (o Order[T]) Less(a, b T) bool {
+	return o(a, b) < 0
+}
+
+// LessThan returns a comparator for timsort.Sort.  The elements being
+// sorted must all hold values of type T.
+func (o Order[T]) LessThan() func(a, b interface{}) bool {
+	return func(a, b interface{}) bool {
+		return o(a.(T), b.(T)) < 0
+	}
+}
+
+// NullsFirst returns an Order on pointers that puts nil before any
+// non-nil pointer and compares non-nil pointers by the values they
+// point to.
+func NullsFirst[T any](o Order[T]) Order[*T] {
+	return nulls(o, -1)
+}
+
+// NullsLast is like NullsFirst, except that nil sorts after any non-nil
+// pointer.
+func NullsLast[T any](o Order[T]) Order[*T] {
+	return nulls(o, 1)
+}
+
+func nulls[T any](o Order[T], nilSign int) Order[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return nilSign
+		case b == nil:
+			return -nilSign
+		}
+		return o(*a, *b)
+	}
+}
@@ -0,0 +1,156 @@
+package order
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/psilva261/timsort/v2"
+)
+
+type person struct {
+	name  string
+	age   int
+	level string
+	order int
+}
+
+var levels = []string{"debug", "info", "warn", "error"}
+
+func makePeople(size int) []person {
+	names := []string{"ann", "bob", "cid", "dee"}
+	all := []string{"debug", "info", "warn", "error", "trace"}
+	a := make([]person, size)
+
+	for i := 0; i < size; i++ {
+		a[i] = person{
+			name:  names[rand.Intn(len(names))],
+			age:   rand.Intn(10),
+			level: all[rand.Intn(len(all))],
+			order: i,
+		}
+	}
+
+	return a
+}
+
+var (
+	byName  = By(func(p person) string { return p.name })
+	byAge   = By(func(p person) int { return p.age })
+	byLevel = Rank(func(p person) string { return p.level }, levels...)
+)
+
+// checkStrictWeakOrder verifies irreflexivity, asymmetry, transitivity and
+// transitivity of equivalence of less over all triples of a.
+func checkStrictWeakOrder[T any](t *testing.T, a []T, less func(a, b T) bool) {
+	t.Helper()
+	equiv := func(x, y T) bool { return !less(x, y) && !less(y, x) }
+	for _, x := range a {
+		if less(x, x) {
+			t.Fatalf("not irreflexive: %v", x)
+		}
+		for _, y := range a {
+			if less(x, y) && less(y, x) {
+				t.Fatalf("not asymmetric: %v %v", x, y)
+			}
+			for _, z := range a {
+				if less(x, y) && less(y, z) && !less(x, z) {
+					t.Fatalf("not transitive: %v %v %v", x, y, z)
+				}
+				if equiv(x, y) && equiv(y, z) && !equiv(x, z) {
+					t.Fatalf("equivalence not transitive: %v %v %v", x, y, z)
+				}
+			}
+		}
+	}
+}
+
+func TestStrictWeakOrder(t *testing.T) {
+	a := makePeople(40)
+
+	orders := map[string]Order[person]{
+		"name":            byName,
+		"name,age":        byName.ThenBy(byAge),
+		"name,-age":       byName.ThenBy(byAge.Reverse()),
+		"-(name,age)":     byName.ThenBy(byAge).Reverse(),
+		"level,name,age":  byLevel.ThenBy(byName).ThenBy(byAge),
+		"-level":          byLevel.Reverse(),
+		"age,level,-name": byAge.ThenBy(byLevel).ThenBy(byName.Reverse()),
+	}
+	for desc, o := range orders {
+		t.Run(desc, func(t *testing.T) {
+			checkStrictWeakOrder(t, a, o.Less)
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	a := makePeople(10000)
+	b := make([]person, len(a))
+	copy(b, a)
+
+	o := byLevel.ThenBy(byName).ThenBy(byAge.Reverse())
+	timsort.Slice(a, o.Less)
+	sort.SliceStable(b, func(i, j int) bool { return o.Less(b[i], b[j]) })
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("index %d: got %v, want %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	people := makePeople(1000)
+	a := make([]interface{}, len(people))
+	for i, p := range people {
+		a[i] = p
+	}
+
+	timsort.Sort(a, byName.ThenBy(byAge).LessThan())
+	for i := 1; i < len(a); i++ {
+		x, y := a[i-1].(person), a[i].(person)
+		if byName.ThenBy(byAge)(x, y) > 0 || x.name == y.name && x.age == y.age && x.order > y.order {
+			t.Fatalf("index %d: %v before %v", i, x, y)
+		}
+	}
+}
+
+func TestInts(t *testing.T) {
+	a := rand.Perm(1000)
+
+	timsort.Ints(a, Natural[int]().Reverse().Less)
+	for i := range a {
+		if a[i] != len(a)-1-i {
+			t.Fatalf("index %d: got %d", i, a[i])
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	a := []string{"warn", "fatal", "debug", "error", "trace", "info"}
+
+	timsort.Slice(a, Rank(func(s string) string { return s }, levels...).Less)
+
+	want := []string{"debug", "info", "warn", "error", "fatal", "trace"}
+	for i := range a {
+		if a[i] != want[i] {
+			t.Fatalf("got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestNulls(t *testing.T) {
+	one, two := 1, 2
+	a := []*int{&two, nil, &one, nil}
+
+	timsort.Slice(a, NullsFirst(Natural[int]()).Less)
+	if a[0] != nil || a[1] != nil || *a[2] != 1 || *a[3] != 2 {
+		t.Errorf("nulls first: got %v", a)
+	}
+
+	timsort.Slice(a, NullsLast(Natural[int]()).Less)
+	if *a[0] != 1 || *a[1] != 2 || a[2] != nil || a[3] != nil {
+		t.Errorf("nulls last: got %v", a)
+	}
+}
@@ -0,0 +1,123 @@
+package timsort
+
+// Reorderer restores the order of a stream of elements that arrive nearly
+// sorted, with bounded disorder, such as events whose timestamps are at
+// most a few seconds out of order.  Elements are pushed one at a time and
+// handed to an emit function in sorted order once no element that is yet
+// to arrive can sort before them.  Equal elements are emitted in the order
+// they arrived.
+//
+// Elements are held in a buffer that is sorted in batches, and are only
+// emitted when a batch is sorted, not as soon as they become final.  With
+// NewReorderer a batch is sorted every window pushes, so an element may be
+// held for up to window pushes after it is final.  With NewReordererFunc a
+// batch is sorted once the buffer holds twice as many elements as the last
+// batch held back, and at least 32.
+//
+// The elements held back from the previous batch form a sorted run and the
+// new ones are nearly sorted, so each sort finds long natural runs and
+// costs close to linear time.
+//
+// Elements that arrive later than the bound allows are emitted with the
+// next batch, out of order.  A Reorderer is not safe for concurrent use.
+type Reorderer[T any] struct {
+	lt   LessFunc[T]
+	emit func(T)
+
+	window int                    // Disorder bound by count, if final is nil
+	final  func(v, newest T) bool // Disorder bound by key
+
+	buf       []T
+	next      int  // Buffer length at which to sort the next batch
+	newest    T    // Greatest element pushed so far
+	hasNewest bool // Whether newest is set
+}
+
+// NewReorderer returns a Reorderer for streams in which every element
+// arrives at most window positions later than its position in sorted
+// order.  It holds at most 2*window elements.
+func NewReorderer[T any](window int, lt LessFunc[T], emit func(T)) *Reorderer[T] {
+	if window < 0 {
+		window = 0
+	}
+	return &Reorderer[T]{
+		lt:     lt,
+		emit:   emit,
+		window: window,
+		next:   window + max(window, 1)}
+}
+
+// NewReordererFunc returns a Reorderer for streams whose disorder is bounded
+// by key distance.  final reports whether v is final, that is whether no
+// element that arrives after newest, the greatest element pushed so far,
+// can sort before v.  For events that are at most five seconds late:
+//
+//	final := func(v, newest Event) bool {
+//		return v.Time.Before(newest.Time.Add(-5 * time.Second))
+//	}
+func NewReordererFunc[T any](final func(v, newest T) bool, lt LessFunc[T], emit func(T)) *Reorderer[T] {
+	return &Reorderer[T]{
+		lt:    lt,
+		emit:  emit,
+		final: final,
+		next:  minMerge}
+}
+
+// Push adds v to the stream, and emits the elements that became final if
+// a batch is due.
+func (r *Reorderer[T]) Push(v T) {
+	if !r.hasNewest || r.lt(r.newest, v) {
+		r.newest = v
+		r.hasNewest = true
+	}
+
+	r.buf = append(r.buf, v)
+	if len(r.buf) >= r.next {
+		r.release()
+	}
+}
+
+// Len returns the number of elements held.
+func (r *Reorderer[T]) Len() int {
+	return len(r.buf)
+}
+
+// Flush emits all elements held, in sorted order.  It is called at the end
+// of the stream.
+func (r *Reorderer[T]) Flush() {
+	Slice(r.buf, r.lt)
+	r.drop(len(r.buf))
+}
+
+/**
+ * Sorts the buffer, emits its final prefix and schedules the next batch.
+ */
+func (r *Reorderer[T]) release() {
+	Slice(r.buf, r.lt)
+
+	n := 0
+	if r.final == nil {
+		// The len(buf)-window least elements of the stream have arrived
+		n = len(r.buf) - r.window
+	} else {
+		for n < len(r.buf) && r.final(r.buf[n], r.newest) {
+			n++
+		}
+	}
+	r.drop(n)
+
+	if r.final != nil {
+		r.next = max(2*len(r.buf), minMerge)
+	}
+}
+
+// drop emits the first n elements of the buffer and removes them
+func (r *Reorderer[T]) drop(n int) {
+	for _, v := range r.buf[:n] {
+		r.emit(v)
+	}
+
+	rest := copy(r.buf, r.buf[n:])
+	clear(r.buf[rest:])
+	r.buf = r.buf[:rest]
+}
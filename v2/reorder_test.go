@@ -0,0 +1,84 @@
+package timsort
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// makeLateVals returns vals in sorted key order, with every element delayed
+// by at most maxLate positions
+func makeLateVals(size, maxLate int) []val {
+	a := make([]val, size)
+	arrival := make(map[val]int)
+	for i := range a {
+		a[i] = val{i / 2, i}
+		arrival[a[i]] = i + rand.Intn(maxLate+1)
+	}
+
+	Slice(a, func(x, y val) bool { return arrival[x] < arrival[y] })
+	return a
+}
+
+func TestReorderer(t *testing.T) {
+	for _, window := range []int{0, 1, 5, 100} {
+		a := makeLateVals(10000, window)
+
+		var got []val
+		r := NewReorderer(window, valKeyLessThan, func(v val) {
+			got = append(got, v)
+		})
+		for i, v := range a {
+			r.Push(v)
+			if r.Len() > 2*window+1 {
+				t.Fatalf("window=%d: holding %d elements after %d", window, r.Len(), i)
+			}
+		}
+		r.Flush()
+
+		want := slices.Clone(a)
+		Slice(want, valKeyLessThan)
+		if !slices.Equal(got, want) {
+			t.Fatalf("window=%d: not reordered", window)
+		}
+	}
+}
+
+func TestReordererFunc(t *testing.T) {
+	// keys are at most 10 less than the greatest key seen before them
+	a := make([]int, 100000)
+	newest := 0
+	for i := range a {
+		a[i] = max(0, newest-rand.Intn(11))
+		if rand.Intn(2) == 0 {
+			a[i] = newest + rand.Intn(3)
+		}
+		newest = max(newest, a[i])
+	}
+
+	compares := 0
+	var got []int
+	r := NewReordererFunc(func(v, newest int) bool {
+		return v < newest-10
+	}, func(x, y int) bool {
+		compares++
+		return x < y
+	}, func(v int) {
+		got = append(got, v)
+	})
+	for _, v := range a {
+		r.Push(v)
+	}
+	held := r.Len()
+	r.Flush()
+
+	if !slices.IsSorted(got) || len(got) != len(a) {
+		t.Fatal("not reordered")
+	}
+	if held > 1000 {
+		t.Errorf("holding %d elements at the end", held)
+	}
+	if compares > 20*len(a) {
+		t.Errorf("%d compares for %d elements", compares, len(a))
+	}
+}
@@ -0,0 +1,183 @@
+package timsort
+
+import (
+	"iter"
+	"sort"
+)
+
+// CompactionPolicy decides which runs of a RunStore to merge.  It is given
+// the lengths of the runs, oldest first, and returns the index i of a pair
+// of adjacent runs i and i+1 to merge, or -1 if no merge is due.  It is
+// called repeatedly until it returns -1.
+type CompactionPolicy func(runLens []int) int
+
+// TimsortPolicy is the merge policy of the sort itself: it keeps the run
+// lengths, from the newest run backwards, growing at least as fast as the
+// Fibonacci numbers, so that there are O(log n) runs and every element
+// takes part in O(log n) merges.
+func TimsortPolicy(runLens []int) int {
+	if len(runLens) < 2 {
+		return -1
+	}
+
+	n := len(runLens) - 2
+	if (n > 0 && runLens[n-1] <= runLens[n]+runLens[n+1]) ||
+		(n > 1 && runLens[n-2] <= runLens[n-1]+runLens[n]) {
+		if runLens[n-1] < runLens[n+1] {
+			n--
+		}
+		return n
+	} else if runLens[n] <= runLens[n+1] {
+		return n
+	}
+	return -1 // Invariant is established
+}
+
+// RunStoreStats reports the compaction work of a RunStore.
+type RunStoreStats struct {
+	Batches int // Batches added
+	Merges  int // Pairs of runs merged
+	Moved   int // Elements copied by merges
+	MaxRuns int // Greatest number of runs held at once
+}
+
+// RunStore is an in-memory, LSM-style store of sorted runs.  Batches are
+// added as new runs, which are merged with older runs according to a
+// CompactionPolicy, by default the one timsort uses for its pending runs.
+// Lookups search all runs, merged or not.  Equal elements are kept, and
+// returned, in the order they were added.
+//
+// A RunStore is not safe for concurrent use.
+type RunStore[T any] struct {
+	lt     LessFunc[T]
+	policy CompactionPolicy
+	runs   [][]T // Oldest first
+	n      int
+	stats  RunStoreStats
+}
+
+// NewRunStore returns an empty RunStore ordered by lt that compacts with
+// policy, or with TimsortPolicy if policy is nil.
+func NewRunStore[T any](lt LessFunc[T], policy CompactionPolicy) *RunStore[T] {
+	if policy == nil {
+		policy = TimsortPolicy
+	}
+	return &RunStore[T]{
+		lt:     lt,
+		policy: policy}
+}
+
+// Add adds a copy of batch as a new run, sorting it first, and merges runs
+// as the policy asks for.
+func (s *RunStore[T]) Add(batch []T) {
+	if len(batch) == 0 {
+		return
+	}
+
+	run := make([]T, len(batch))
+	copy(run, batch)
+	Slice(run, s.lt)
+
+	s.runs = append(s.runs, run)
+	s.n += len(run)
+	s.stats.Batches++
+	s.stats.MaxRuns = max(s.stats.MaxRuns, len(s.runs))
+
+	lens := make([]int, 0, len(s.runs))
+	for {
+		lens = lens[:0]
+		for _, r := range s.runs {
+			lens = append(lens, len(r))
+		}
+
+		i := s.policy(lens)
+		if i < 0 {
+			return
+		}
+		s.mergeAt(i)
+	}
+}
+
+// Compact merges all runs into one.
+func (s *RunStore[T]) Compact() {
+	for len(s.runs) > 1 {
+		s.mergeAt(len(s.runs) - 2)
+	}
+}
+
+func (s *RunStore[T]) mergeAt(i int) {
+	a, b := s.runs[i], s.runs[i+1]
+	s.runs[i] = Merge(make([]T, 0, len(a)+len(b)), a, b, s.lt)
+	s.runs = append(s.runs[:i+1], s.runs[i+2:]...)
+
+	s.stats.Merges++
+	s.stats.Moved += len(a) + len(b)
+}
+
+// Len returns the number of elements.
+func (s *RunStore[T]) Len() int {
+	return s.n
+}
+
+// Runs returns the number of runs.
+func (s *RunStore[T]) Runs() int {
+	return len(s.runs)
+}
+
+// Stats returns the compaction statistics.
+func (s *RunStore[T]) Stats() RunStoreStats {
+	return s.stats
+}
+
+// Contains reports whether an element equal to key is present.
+func (s *RunStore[T]) Contains(key T) bool {
+	for _, run := range s.runs {
+		if i := s.search(run, key); i < len(run) && !s.lt(key, run[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns an iterator over the elements equal to key, in the order
+// they were added.
+func (s *RunStore[T]) Lookup(key T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, run := range s.runs {
+			for i := s.search(run, key); i < len(run) && !s.lt(key, run[i]); i++ {
+				if !yield(run[i]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the elements that are not less than lo and
+// less than hi, in sorted order, merging the matching part of every run on
+// the fly.  The store must not be modified during the iteration.
+func (s *RunStore[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		parts := make([][]T, len(s.runs))
+		for i, run := range s.runs {
+			start := s.search(run, lo)
+			parts[i] = run[start:max(start, s.search(run, hi))]
+		}
+		MergeKFunc(parts, s.lt, yield)
+	}
+}
+
+// All returns an iterator over all elements in sorted order.  The store
+// must not be modified during the iteration.
+func (s *RunStore[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		MergeKFunc(s.runs, s.lt, yield)
+	}
+}
+
+// search returns the index of the first element of run not less than key
+func (s *RunStore[T]) search(run []T, key T) int {
+	return sort.Search(len(run), func(i int) bool {
+		return !s.lt(run[i], key)
+	})
+}
@@ -0,0 +1,87 @@
+package timsort
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRunStore(t *testing.T) {
+	s := NewRunStore(valKeyLessThan, nil)
+	var all []val
+
+	for batch := 0; batch < 500; batch++ {
+		b := makeRandomVals(1 + rand.Intn(100))
+		for i := range b {
+			b[i].order = len(all) + i
+		}
+		s.Add(b)
+		all = append(all, b...)
+
+		if s.Runs() > 2*bits.Len(uint(len(all)))+2 {
+			t.Fatalf("batch %d: %d runs for %d elements", batch, s.Runs(), len(all))
+		}
+	}
+
+	want := slices.Clone(all)
+	Slice(want, valKeyLessThan)
+	if got := slices.Collect(s.All()); !slices.Equal(got, want) {
+		t.Fatal("all: not sorted by key and insertion")
+	}
+	if s.Len() != len(all) {
+		t.Fatalf("got length %d, want %d", s.Len(), len(all))
+	}
+
+	lo, hi := val{key: 20}, val{key: 30}
+	var wantRange []val
+	for _, v := range want {
+		if v.key >= 20 && v.key < 30 {
+			wantRange = append(wantRange, v)
+		}
+	}
+	if got := slices.Collect(s.Range(lo, hi)); !slices.Equal(got, wantRange) {
+		t.Fatal("range differs")
+	}
+	if got := slices.Collect(s.Range(hi, lo)); len(got) != 0 {
+		t.Fatalf("inverted range yields %d elements", len(got))
+	}
+
+	var wantLookup []val
+	for _, v := range want {
+		if v.key == 42 {
+			wantLookup = append(wantLookup, v)
+		}
+	}
+	if got := slices.Collect(s.Lookup(val{key: 42})); !slices.Equal(got, wantLookup) {
+		t.Fatalf("lookup: got %v, want %v", got, wantLookup)
+	}
+	if !s.Contains(val{key: 42}) || s.Contains(val{key: 100}) {
+		t.Error("contains")
+	}
+
+	stats := s.Stats()
+	if stats.Batches != 500 || stats.Merges == 0 || stats.MaxRuns < s.Runs() {
+		t.Errorf("stats: %+v", stats)
+	}
+
+	s.Compact()
+	if s.Runs() != 1 || !slices.Equal(slices.Collect(s.All()), want) {
+		t.Error("compact")
+	}
+}
+
+func TestRunStorePolicy(t *testing.T) {
+	// never merge
+	s := NewRunStore(intLessThan, func([]int) int { return -1 })
+	for i := 0; i < 10; i++ {
+		s.Add([]int{i, 20 - i})
+	}
+
+	if s.Runs() != 10 || s.Stats().Merges != 0 {
+		t.Errorf("got %d runs, %d merges", s.Runs(), s.Stats().Merges)
+	}
+	if got := slices.Collect(s.All()); !slices.IsSorted(got) || len(got) != 20 {
+		t.Errorf("got %v", got)
+	}
+}
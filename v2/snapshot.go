@@ -0,0 +1,123 @@
+package timsort
+
+import (
+	"iter"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// SnapshotIndex is a sorted index that one or more writers update in
+// batches while readers query it concurrently.  Every update builds a new,
+// immutable version of the index by merging the sorted batch into a copy
+// of the current one and publishes it with an atomic pointer swap, so
+// readers never block, never wait for writers and always see a consistent
+// snapshot.  Equal elements are kept in the order they were inserted.
+//
+// Updates copy the whole index, which suits indexes that are read much
+// more often than they are written, with writes batched.
+type SnapshotIndex[T any] struct {
+	lt      LessFunc[T]
+	mu      sync.Mutex // Serializes writers
+	current atomic.Pointer[Snapshot[T]]
+}
+
+// Snapshot is an immutable version of a SnapshotIndex.
+type Snapshot[T any] struct {
+	lt      LessFunc[T]
+	items   []T
+	version uint64
+}
+
+// NewSnapshotIndex returns an empty SnapshotIndex ordered by lt.
+func NewSnapshotIndex[T any](lt LessFunc[T]) *SnapshotIndex[T] {
+	x := &SnapshotIndex[T]{lt: lt}
+	x.current.Store(&Snapshot[T]{lt: lt})
+	return x
+}
+
+// Insert adds the elements of batch, which is not modified, and publishes
+// the new version.
+func (x *SnapshotIndex[T]) Insert(batch []T) {
+	if len(batch) == 0 {
+		return
+	}
+
+	sorted := make([]T, len(batch))
+	copy(sorted, batch)
+	Slice(sorted, x.lt)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	old := x.current.Load()
+	items := Merge(make([]T, 0, len(old.items)+len(sorted)), old.items, sorted, x.lt)
+	x.current.Store(&Snapshot[T]{
+		lt:      x.lt,
+		items:   items,
+		version: old.version + 1})
+}
+
+// Snapshot returns the current version.  It never blocks.
+func (x *SnapshotIndex[T]) Snapshot() *Snapshot[T] {
+	return x.current.Load()
+}
+
+// Version returns the number of updates that led to this snapshot.
+func (s *Snapshot[T]) Version() uint64 {
+	return s.version
+}
+
+// Len returns the number of elements.
+func (s *Snapshot[T]) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th element in sorted order.
+func (s *Snapshot[T]) At(i int) T {
+	return s.items[i]
+}
+
+// Search returns the index of the first element not less than key, or Len
+// if there is none.
+func (s *Snapshot[T]) Search(key T) int {
+	return sort.Search(len(s.items), func(i int) bool {
+		return !s.lt(s.items[i], key)
+	})
+}
+
+// SearchFrom is like Search, but gallops from hint, which makes successive
+// lookups of nearby keys cheap when each one passes the previous result as
+// hint.
+func (s *Snapshot[T]) SearchFrom(key T, hint int) int {
+	return GallopLeft(s.items, key, hint, s.lt)
+}
+
+// Contains reports whether an element equal to key is present.
+func (s *Snapshot[T]) Contains(key T) bool {
+	i := s.Search(key)
+	return i < len(s.items) && !s.lt(key, s.items[i])
+}
+
+// Range returns an iterator over the elements that are not less than lo and
+// less than hi, in sorted order.
+func (s *Snapshot[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.Search(lo); i < len(s.items) && s.lt(s.items[i], hi); i++ {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in sorted order.
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s.items {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package timsort
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIndex(t *testing.T) {
+	x := NewSnapshotIndex(valKeyLessThan)
+	empty := x.Snapshot()
+
+	var all []val
+	for batch := 0; batch < 50; batch++ {
+		b := makeRandomVals(100)
+		for i := range b {
+			b[i].order = len(all) + i
+		}
+		x.Insert(b)
+		all = append(all, b...)
+	}
+
+	s := x.Snapshot()
+	want := slices.Clone(all)
+	Slice(want, valKeyLessThan)
+	if got := slices.Collect(s.All()); !slices.Equal(got, want) {
+		t.Fatal("not sorted by key and insertion")
+	}
+	if s.Version() != 50 || s.Len() != len(all) || empty.Len() != 0 {
+		t.Errorf("version %d, length %d", s.Version(), s.Len())
+	}
+
+	i := s.Search(val{key: 50})
+	if s.At(i).key != 50 || s.At(i-1).key != 49 || s.SearchFrom(val{key: 50}, 0) != i {
+		t.Errorf("search: got %d", i)
+	}
+	if !s.Contains(val{key: 99}) || s.Contains(val{key: 100}) {
+		t.Error("contains")
+	}
+	for v := range s.Range(val{key: 10}, val{key: 11}) {
+		if v.key != 10 {
+			t.Errorf("range: got %v", v)
+		}
+	}
+}
+
+func TestSnapshotIndexConcurrent(t *testing.T) {
+	x := NewSnapshotIndex(intLessThan)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := 0; b < 50; b++ {
+				x.Insert(makeRandomArrayI(100))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			s := x.Snapshot()
+			if s.Len() != 4*50*100 || s.Version() != 200 {
+				t.Errorf("got %d elements in version %d", s.Len(), s.Version())
+			}
+			return
+		default:
+		}
+
+		s := x.Snapshot()
+		got := slices.Collect(s.All())
+		if !slices.IsSorted(got) || uint64(len(got)) != 100*s.Version() {
+			t.Fatalf("inconsistent snapshot: %d elements in version %d", len(got), s.Version())
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package timsort
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"iter"
+	"os"
+	"slices"
+)
+
+// SortChanOptions configures SortChan.  The zero value is usable.
+type SortChanOptions struct {
+	// ChunkSize is the number of elements sorted at a time, 65536 if
+	// zero.
+	ChunkSize int
+
+	// MaxInMemory is the number of elements held in memory, 4*ChunkSize
+	// if zero.  Sorted chunks that do not fit are spilled to temporary
+	// files.
+	MaxInMemory int
+
+	// MaxFanIn is the greatest number of spilled runs merged at once, 64
+	// if less than 2.  Whenever that many runs of the same size pile up
+	// they are merged into one, so at most MaxFanIn+1 files are open at a
+	// time.
+	MaxFanIn int
+
+	// TempDir is the directory for spill files, os.TempDir() if empty.
+	TempDir string
+}
+
+// SortChan reads all elements from in until it is closed, and sends them,
+// stably sorted, on the returned channel, which is closed at the end.
+//
+// Elements are collected into chunks, which are sorted as they fill up.
+// Once more than opts.MaxInMemory elements are held, further chunks are
+// spilled to temporary files, encoded with encoding/gob, so T must be
+// encodable by it, with exported fields, if the input may not fit.
+// Spilled runs are merged in passes of at most opts.MaxFanIn runs, and
+// when in is closed, all runs are merged with a tournament tree and
+// streamed to the output.  Besides the elements in memory, each run being
+// merged holds a read buffer and a decoder.
+//
+// If ctx is canceled, or spilling fails, SortChan stops reading and
+// sending, removes its temporary files and closes the output channel.  It
+// does not drain in.  The returned function waits until the output channel
+// is closed and returns the error that ended the sort early, if any.  The
+// error is ctx.Err() if the context was canceled.
+func SortChan[T any](ctx context.Context, in <-chan T, lt LessFunc[T], opts SortChanOptions) (<-chan T, func() error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1 << 16
+	}
+	if opts.MaxInMemory <= 0 {
+		opts.MaxInMemory = 4 * opts.ChunkSize
+	}
+	if opts.MaxFanIn < 2 {
+		opts.MaxFanIn = 64
+	}
+
+	out := make(chan T)
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		defer close(out)
+		s := &chanSorter[T]{lt: lt, opts: opts}
+		err = s.run(ctx, in, out)
+		s.cleanup()
+	}()
+
+	return out, func() error {
+		<-done
+		return err
+	}
+}
+
+// chanSorter holds the sorted chunks of a SortChan call.  The chunks in
+// memory arrived before all spilled runs, and spilled runs are kept in
+// arrival order, so that merging them in that order is stable.
+type chanSorter[T any] struct {
+	lt       LessFunc[T]
+	opts     SortChanOptions
+	chunks   [][]T
+	inMemory int
+	spills   []spillRun
+	err      error // Decoding error while merging
+}
+
+// spillRun is a sorted run in a temporary file.  Runs spilled directly
+// are on level 0, and merging runs of level l gives a run of level l+1.
+type spillRun struct {
+	name  string
+	n     int
+	level int
+}
+
+func (s *chanSorter[T]) run(ctx context.Context, in <-chan T, out chan<- T) error {
+	buf := make([]T, 0, s.opts.ChunkSize)
+
+read:
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-in:
+			if !ok {
+				break read
+			}
+			buf = append(buf, v)
+			if len(buf) == s.opts.ChunkSize {
+				if err := s.addChunk(ctx, buf); err != nil {
+					return err
+				}
+				buf = make([]T, 0, s.opts.ChunkSize)
+			}
+		}
+	}
+	if err := s.addChunk(ctx, buf); err != nil {
+		return err
+	}
+
+	// Leave at most MaxFanIn spilled runs for the final merge
+	for len(s.spills) > s.opts.MaxFanIn {
+		if err := s.mergeSpills(ctx, len(s.spills)-s.opts.MaxFanIn); err != nil {
+			return err
+		}
+	}
+
+	seqs := make([]iter.Seq[T], 0, len(s.chunks)+len(s.spills))
+	for _, c := range s.chunks {
+		seqs = append(seqs, slices.Values(c))
+	}
+	for _, r := range s.spills {
+		seqs = append(seqs, s.readRun(r))
+	}
+
+	var err error
+	MergeKSeq(seqs, s.lt, func(v T) bool {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		case out <- v:
+			return true
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return s.err
+}
+
+// addChunk sorts the chunk and keeps it in memory or spills it to a file
+func (s *chanSorter[T]) addChunk(ctx context.Context, chunk []T) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	Slice(chunk, s.lt)
+
+	if s.spills == nil && s.inMemory+len(chunk) <= s.opts.MaxInMemory {
+		s.inMemory += len(chunk)
+		s.chunks = append(s.chunks, chunk)
+		return nil
+	}
+
+	r, err := s.writeRun(slices.Values(chunk), len(chunk), 0)
+	if err != nil {
+		return err
+	}
+	s.spills = append(s.spills, r)
+
+	// Merge runs of the same level as soon as MaxFanIn of them pile up
+	for {
+		i := len(s.spills) - s.opts.MaxFanIn
+		if i < 0 || s.spills[i].level != r.level {
+			return nil
+		}
+		if err := s.mergeSpills(ctx, i); err != nil {
+			return err
+		}
+		r = s.spills[i]
+	}
+}
+
+// mergeSpills merges the spilled runs from index i on into one run
+func (s *chanSorter[T]) mergeSpills(ctx context.Context, i int) error {
+	runs := s.spills[i:]
+	seqs := make([]iter.Seq[T], len(runs))
+	n, level := 0, 0
+	for j, r := range runs {
+		seqs[j] = s.readRun(r)
+		n += r.n
+		level = max(level, r.level+1)
+	}
+
+	merged := func(yield func(T) bool) {
+		MergeKSeq(seqs, s.lt, func(v T) bool {
+			return ctx.Err() == nil && yield(v)
+		})
+	}
+	r, err := s.writeRun(merged, n, level)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = s.err
+	}
+	if err != nil {
+		if r.name != "" {
+			os.Remove(r.name)
+		}
+		return err
+	}
+
+	for _, old := range runs {
+		os.Remove(old.name)
+	}
+	s.spills = append(s.spills[:i], r)
+	return nil
+}
+
+// writeRun writes the n elements of seq to a new temporary file
+func (s *chanSorter[T]) writeRun(seq iter.Seq[T], n, level int) (spillRun, error) {
+	f, err := os.CreateTemp(s.opts.TempDir, "timsort-*")
+	if err != nil {
+		return spillRun{}, err
+	}
+	r := spillRun{name: f.Name(), n: n, level: level}
+
+	w := bufio.NewWriter(f)
+	enc := gob.NewEncoder(w)
+	for v := range seq {
+		if err = enc.Encode(v); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(r.name)
+		return spillRun{}, err
+	}
+	return r, nil
+}
+
+// readRun returns a sequence over the elements of a spilled run.  The file
+// is only open while the sequence is iterated.
+func (s *chanSorter[T]) readRun(r spillRun) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		f, err := os.Open(r.name)
+		if err != nil {
+			s.err = errors.Join(s.err, err)
+			return
+		}
+		defer f.Close()
+
+		dec := gob.NewDecoder(bufio.NewReader(f))
+		for i := 0; i < r.n; i++ {
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				s.err = errors.Join(s.err, err)
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s *chanSorter[T]) cleanup() {
+	for _, r := range s.spills {
+		os.Remove(r.name)
+	}
+	s.spills = nil
+}
@@ -0,0 +1,169 @@
+package timsort
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+// spillRec is like val, but with exported fields so that gob can spill it
+type spillRec struct {
+	Key, Order int
+}
+
+func spillRecLessThan(a, b spillRec) bool {
+	return a.Key < b.Key
+}
+
+func sendAll[T any](a []T) <-chan T {
+	in := make(chan T)
+	go func() {
+		defer close(in)
+		for _, v := range a {
+			in <- v
+		}
+	}()
+	return in
+}
+
+func TestSortChan(t *testing.T) {
+	for _, size := range []int{0, 1, 100, 10000} {
+		a := make([]spillRec, size)
+		for i := range a {
+			a[i] = spillRec{rand.Intn(100), i}
+		}
+		want := slices.Clone(a)
+		Slice(want, spillRecLessThan)
+
+		for _, opts := range []SortChanOptions{
+			{},
+			{ChunkSize: 64, MaxInMemory: 256, TempDir: t.TempDir()},
+			{ChunkSize: 7, MaxInMemory: 1, TempDir: t.TempDir()},
+			{ChunkSize: 7, MaxInMemory: 20, MaxFanIn: 3, TempDir: t.TempDir()},
+		} {
+			out, errf := SortChan(context.Background(), sendAll(a), spillRecLessThan, opts)
+
+			var got []spillRec
+			for v := range out {
+				got = append(got, v)
+			}
+			if err := errf(); err != nil {
+				t.Errorf("size %d: %v", size, err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("size %d, chunk %d: not sorted stably", size, opts.ChunkSize)
+			}
+
+			if opts.TempDir != "" {
+				if files, _ := os.ReadDir(opts.TempDir); len(files) != 0 {
+					t.Errorf("size %d: %d spill files left", size, len(files))
+				}
+			}
+		}
+	}
+}
+
+func TestSortChanFanIn(t *testing.T) {
+	// 1000 spilled runs are merged three at a time while they are read,
+	// so only a few files exist at any time
+	dir := t.TempDir()
+	in := make(chan spillRec)
+	maxFiles := 0
+	go func() {
+		defer close(in)
+		for i := 0; i < 10000; i++ {
+			in <- spillRec{rand.Intn(100), i}
+			if files, _ := os.ReadDir(dir); len(files) > maxFiles {
+				maxFiles = len(files)
+			}
+		}
+	}()
+
+	out, errf := SortChan(context.Background(), in, spillRecLessThan, SortChanOptions{
+		ChunkSize:   10,
+		MaxInMemory: 10,
+		MaxFanIn:    3,
+		TempDir:     dir,
+	})
+	var got []spillRec
+	for v := range out {
+		got = append(got, v)
+	}
+	if err := errf(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 10000 || !slices.IsSortedFunc(got, func(a, b spillRec) int {
+		if a.Key != b.Key {
+			return a.Key - b.Key
+		}
+		return a.Order - b.Order
+	}) {
+		t.Error("not sorted stably")
+	}
+	if maxFiles > 20 {
+		t.Errorf("%d spill files at once", maxFiles)
+	}
+}
+
+func TestSortChanCancel(t *testing.T) {
+	for _, name := range []string{"reading", "sending"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		dir := t.TempDir()
+
+		in := make(chan spillRec)
+		sent := make(chan struct{})
+		go func() {
+			defer close(in)
+			for i := 0; i < 1000; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case in <- spillRec{1000 - i, i}:
+				}
+				if i == 500 {
+					close(sent)
+				}
+			}
+		}()
+		out, errf := SortChan(ctx, in, spillRecLessThan, SortChanOptions{
+			ChunkSize:   10,
+			MaxInMemory: 10,
+			TempDir:     dir,
+		})
+
+		if name == "reading" {
+			<-sent
+		} else {
+			<-out
+		}
+		cancel()
+		for range out {
+		}
+
+		if err := errf(); err != context.Canceled {
+			t.Errorf("%s: got error %v, want %v", name, err, context.Canceled)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("%s: %d spill files left", name, len(files))
+		}
+	}
+}
+
+func TestSortChanSpillError(t *testing.T) {
+	// val has no exported fields, so gob cannot encode it
+	out, errf := SortChan(context.Background(), sendAll(makeRandomVals(300)), valKeyLessThan, SortChanOptions{
+		ChunkSize:   100,
+		MaxInMemory: 100,
+	})
+	n := 0
+	for range out {
+		n++
+	}
+
+	if err := errf(); err == nil {
+		t.Errorf("no error, %d elements sent", n)
+	}
+}
@@ -0,0 +1,114 @@
+package timsort
+
+import (
+	"iter"
+	"sort"
+)
+
+// SortedSlice is a slice that keeps its elements sorted.  Equal elements are
+// kept in the order they were added.
+//
+// Additions are buffered and merged into the sorted elements in one go,
+// with InsertSorted, the next time the elements are read.  So adding many
+// elements one at a time and then querying costs about as much as a single
+// merge, instead of a sort per addition.
+//
+// The zero value is not usable, create one with NewSortedSlice.  A
+// SortedSlice is not safe for concurrent use; even reads may merge pending
+// additions.
+type SortedSlice[T any] struct {
+	lt      LessFunc[T]
+	items   []T // Sorted elements
+	pending []T // Elements added since the last merge, in order
+}
+
+// NewSortedSlice returns an empty SortedSlice ordered by lt.
+func NewSortedSlice[T any](lt LessFunc[T]) *SortedSlice[T] {
+	return &SortedSlice[T]{lt: lt}
+}
+
+// Add adds v.
+func (s *SortedSlice[T]) Add(v T) {
+	s.pending = append(s.pending, v)
+}
+
+// AddMany adds all elements of vs, in order.
+func (s *SortedSlice[T]) AddMany(vs ...T) {
+	s.pending = append(s.pending, vs...)
+}
+
+// Len returns the number of elements.
+func (s *SortedSlice[T]) Len() int {
+	return len(s.items) + len(s.pending)
+}
+
+// At returns the i-th element in sorted order.
+func (s *SortedSlice[T]) At(i int) T {
+	s.flush()
+	return s.items[i]
+}
+
+// Rank returns the number of elements less than v, which is the index of
+// the first element equal to v if there is one.
+func (s *SortedSlice[T]) Rank(v T) int {
+	s.flush()
+	return sort.Search(len(s.items), func(i int) bool {
+		return !s.lt(s.items[i], v)
+	})
+}
+
+// Contains reports whether an element equal to v is present.
+func (s *SortedSlice[T]) Contains(v T) bool {
+	i := s.Rank(v)
+	return i < len(s.items) && !s.lt(v, s.items[i])
+}
+
+// Remove removes the element equal to v that was added first, and reports
+// whether there was one.
+func (s *SortedSlice[T]) Remove(v T) bool {
+	i := s.Rank(v)
+	if i == len(s.items) || s.lt(v, s.items[i]) {
+		return false
+	}
+
+	copy(s.items[i:], s.items[i+1:])
+	var zero T
+	s.items[len(s.items)-1] = zero
+	s.items = s.items[:len(s.items)-1]
+	return true
+}
+
+// All returns an iterator over the elements in sorted order.  The
+// SortedSlice must not be modified during the iteration.
+func (s *SortedSlice[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.flush()
+		for _, v := range s.items {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the elements that are not less than lo and
+// less than hi, in sorted order.  The SortedSlice must not be modified
+// during the iteration.
+func (s *SortedSlice[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.Rank(lo); i < len(s.items) && s.lt(s.items[i], hi); i++ {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SortedSlice[T]) flush() {
+	if len(s.pending) == 0 {
+		return
+	}
+	s.items = InsertSorted(s.items, s.pending, s.lt)
+	clear(s.pending)
+	s.pending = s.pending[:0]
+}
@@ -0,0 +1,72 @@
+package timsort
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedSlice(t *testing.T) {
+	s := NewSortedSlice(valKeyLessThan)
+	var all []val
+
+	order := 0
+	for round := 0; round < 20; round++ {
+		batch := makeRandomVals(200)
+		for i := range batch {
+			batch[i].order = order
+			order++
+		}
+		if round%2 == 0 {
+			s.AddMany(batch...)
+		} else {
+			for _, v := range batch {
+				s.Add(v)
+			}
+		}
+		all = append(all, batch...)
+
+		// query in between to force merges
+		if s.Len() != len(all) {
+			t.Fatalf("round %d: got length %d, want %d", round, s.Len(), len(all))
+		}
+		want := slices.Clone(all)
+		Slice(want, valKeyLessThan)
+		if got := slices.Collect(s.All()); !slices.Equal(got, want) {
+			t.Fatalf("round %d: not sorted by key and insertion", round)
+		}
+		if got := s.At(len(all) / 2); got != want[len(all)/2] {
+			t.Fatalf("round %d: At got %v, want %v", round, got, want[len(all)/2])
+		}
+	}
+}
+
+func TestSortedSliceQueries(t *testing.T) {
+	s := NewSortedSlice(valKeyLessThan)
+	s.AddMany(val{5, 0}, val{1, 1}, val{3, 2}, val{3, 3}, val{9, 4})
+	s.Add(val{3, 5})
+
+	if got := s.Rank(val{3, -1}); got != 1 {
+		t.Errorf("rank of 3: got %d, want 1", got)
+	}
+	if got := s.Rank(val{4, -1}); got != 4 {
+		t.Errorf("rank of 4: got %d, want 4", got)
+	}
+	if !s.Contains(val{9, -1}) || s.Contains(val{4, -1}) {
+		t.Error("contains")
+	}
+
+	got := slices.Collect(s.Range(val{2, -1}, val{9, -1}))
+	want := []val{{3, 2}, {3, 3}, {3, 5}, {5, 0}}
+	if !slices.Equal(got, want) {
+		t.Errorf("range: got %v, want %v", got, want)
+	}
+
+	if !s.Remove(val{3, -1}) || s.Remove(val{4, -1}) {
+		t.Error("remove")
+	}
+	got = slices.Collect(s.All())
+	want = []val{{1, 1}, {3, 3}, {3, 5}, {5, 0}, {9, 4}}
+	if !slices.Equal(got, want) {
+		t.Errorf("after remove: got %v, want %v", got, want)
+	}
+}
@@ -149,6 +149,19 @@ func newTimSort(a []interface{}, lt LessThan) (h *timSortHandler) {
 
 // Sort an array using the provided comparator
 func Sort(a []interface{}, lt LessThan) {
+	sortWith(a, lt, countRunAndMakeAscending)
+}
+
+// SortDescending sorts an array in descending order of the provided
+// comparator.  Unlike sorting with a reversed comparator, elements that
+// compare equal keep their original relative order and input that is
+// already sorted in either direction is handled in linear time.
+func SortDescending(a []interface{}, lt LessThan) {
+	gt := func(x, y interface{}) bool { return lt(y, x) }
+	sortWith(a, gt, countNonStrictRunAndMakeAscending)
+}
+
+func sortWith(a []interface{}, lt LessThan, countRun func(a []interface{}, lo, hi int, lt LessThan) int) {
 	lo := 0
 	hi := len(a)
 	nRemaining := hi
@@ -159,7 +172,7 @@ func Sort(a []interface{}, lt LessThan) {
 
 	// If array is small, do a "mini-TimSort" with no merges
 	if nRemaining < minMerge {
-		initRunLen := countRunAndMakeAscending(a, lo, hi, lt)
+		initRunLen := countRun(a, lo, hi, lt)
 
 		binarySort(a, lo, hi, lo+initRunLen, lt)
 		return
@@ -175,7 +188,7 @@ func Sort(a []interface{}, lt LessThan) {
 	minRun := minRunLength(nRemaining)
 	for {
 		// Identify next run
-		runLen := countRunAndMakeAscending(a, lo, hi, lt)
+		runLen := countRun(a, lo, hi, lt)
 
 		// If run is short, extend to min(minRun, nRemaining)
 		if runLen < minRun {
@@ -317,6 +330,63 @@ func countRunAndMakeAscending(a []interface{}, lo, hi int, lt LessThan) int {
 	return runHi - lo
 }
 
+/**
+ * Like countRunAndMakeAscending, except that a descending run need not be
+ * strict.  Leading elements that compare equal are counted as part of the
+ * run whatever its direction.  A non-strictly descending run is reversed
+ * and then every block of equal elements in it is reversed back, so that
+ * equal elements keep their original relative order.
+ *
+ * This is used for descending sorts, where input sorted ascending by the
+ * caller's comparator shows up as a descending run that may contain
+ * equal elements.
+ *
+ * @param a the array in which a run is to be counted and possibly reversed
+ * @param lo index of the first element in the run
+ * @param hi index after the last element that may be contained in the run.
+ *        It is required that @code{lo < hi}.
+ * @param c the comparator to used for the sort
+ * @return  the length of the run beginning at the specified position in
+ *          the specified array
+ */
+func countNonStrictRunAndMakeAscending(a []interface{}, lo, hi int, lt LessThan) int {
+	runHi := lo + 1
+	if runHi == hi {
+		return 1
+	}
+
+	// Skip leading equal elements, they belong to a run of either direction
+	for runHi < hi && !lt(a[runHi], a[runHi-1]) && !lt(a[runHi-1], a[runHi]) {
+		runHi++
+	}
+
+	// Find end of run, and reverse range if descending
+	if runHi < hi && lt(a[runHi], a[runHi-1]) { // Descending
+		runHi++
+
+		for runHi < hi && !lt(a[runHi-1], a[runHi]) {
+			runHi++
+		}
+		reverseRange(a, lo, runHi)
+
+		// Restore the original order of equal elements
+		for i := lo; i < runHi; {
+			j := i + 1
+			for j < runHi && !lt(a[i], a[j]) {
+				j++
+			}
+			reverseRange(a, i, j)
+			i = j
+		}
+	} else { // Ascending
+		for runHi < hi && !lt(a[runHi], a[runHi-1]) {
+			runHi++
+		}
+	}
+
+	return runHi - lo
+}
+
 /**
  * Reverse the specified range of the specified array.
  *
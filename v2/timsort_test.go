@@ -352,3 +352,51 @@ func TestBentleyMcIlroy(t *testing.T) {
 		}
 	}
 }
+
+// use this comparator to validate data sorted in descending order
+func KeyDescOrderLessThan(a, b interface{}) bool {
+	if a.(val).key > b.(val).key {
+		return true
+	} else if a.(val).key == b.(val).key {
+		return a.(val).order < b.(val).order
+	}
+
+	return false
+}
+
+func TestSortDescending(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 100, 1024, 100 * 1024} {
+		a := makeRandomArray(size)
+
+		SortDescending(a, KeyLessThan)
+		if !IsSorted(a, KeyDescOrderLessThan) {
+			t.Errorf("size=%d: not sorted", size)
+		}
+	}
+}
+
+func TestSortDescendingPresorted(t *testing.T) {
+	size := 100 * 1024
+	for _, desc := range []bool{false, true} {
+		a := make([]interface{}, size)
+		for i := 0; i < size; i++ {
+			key := i / 3
+			if desc {
+				key = size - i/3
+			}
+			a[i] = val{key, i}
+		}
+
+		compares := 0
+		SortDescending(a, func(a, b interface{}) bool {
+			compares++
+			return KeyLessThan(a, b)
+		})
+		if !IsSorted(a, KeyDescOrderLessThan) {
+			t.Errorf("desc=%v: not sorted", desc)
+		}
+		if compares > 3*size {
+			t.Errorf("desc=%v: %d compares for %d elements", desc, compares, size)
+		}
+	}
+}
@@ -0,0 +1,611 @@
+package timsort
+
+// Accessor gives indexed access to a sequence of elements that need not be
+// stored in a slice, such as a chunked array or a ring buffer.
+type Accessor[T any] interface {
+	// Len is the number of elements.
+	Len() int
+	// Get returns the element at index i.
+	Get(i int) T
+	// Set stores v at index i.
+	Set(i int, v T)
+}
+
+// SortAccessor sorts the elements of a using the provided comparator.  It
+// is the same stable, galloping timsort as Slice, with merges going
+// through a temporary slice, rather than the swap-only insertion and
+// merging of TimSort.
+func SortAccessor[T any](a Accessor[T], lt LessFunc[T]) {
+	lo := 0
+	hi := a.Len()
+	nRemaining := hi
+
+	if nRemaining < 2 {
+		return // Arrays of size 0 and 1 are always sorted
+	}
+
+	// If array is small, do a "mini-TimSort" with no merges
+	if nRemaining < minMerge {
+		initRunLen := countRunAndMakeAscendingA(a, lo, hi, lt)
+
+		binarySortA(a, lo, hi, lo+initRunLen, lt)
+		return
+	}
+
+	/**
+	 * March over the array once, left to right, finding natural runs,
+	 * extending short natural runs to minRun elements, and merging runs
+	 * to maintain stack invariant.
+	 */
+
+	ts := newTimSortA(a, lt)
+	minRun := minRunLength(nRemaining)
+	for {
+		// Identify next run
+		runLen := countRunAndMakeAscendingA(a, lo, hi, lt)
+
+		// If run is short, extend to min(minRun, nRemaining)
+		if runLen < minRun {
+			force := minRun
+			if nRemaining <= minRun {
+				force = nRemaining
+			}
+			binarySortA(a, lo, lo+force, lo+runLen, lt)
+			runLen = force
+		}
+
+		// Push run onto pending-run stack, and maybe merge
+		ts.pushRun(lo, runLen)
+		ts.mergeCollapse()
+
+		// Advance to find next run
+		lo += runLen
+		nRemaining -= runLen
+		if nRemaining == 0 {
+			break
+		}
+	}
+
+	ts.mergeForceCollapse()
+}
+
+// timSortHandlerA is timSortHandlerG for elements behind an Accessor.  The
+// tmp array is a plain slice.
+//
+// Only the gallops are shared with timSortHandlerG.  Running Slice through
+// the Accessor code, with a slice behind the interface, makes every element
+// access in binarySort and the merge loops an indirect call that cannot be
+// inlined, and loses copy for block moves: BenchmarkSliceRandom1K and
+// BenchmarkSliceSorted1K took twice as long that way, and
+// BenchmarkSliceRandom1M a third longer.  So these loops are kept twice,
+// and a fix to either copy must be made to the other.
+type timSortHandlerA[T any] struct {
+	a         Accessor[T]
+	n         int
+	lt        LessFunc[T]
+	minGallop int
+	tmp       []T
+
+	stackSize int
+	runBase   []int
+	runLen    []int
+}
+
+func newTimSortA[T any](a Accessor[T], lt LessFunc[T]) (h *timSortHandlerA[T]) {
+	h = new(timSortHandlerA[T])
+
+	h.a = a
+	h.n = a.Len()
+	h.lt = lt
+	h.minGallop = minGallop
+
+	// Allocate temp storage (which may be increased later if necessary)
+	tmpSize := initialTmpStorageLength
+	if h.n < 2*tmpSize {
+		tmpSize = h.n / 2
+	}
+	h.tmp = make([]T, tmpSize)
+
+	// See newTimSortG for the stack lengths
+	stackLen := 40
+	if h.n < 120 {
+		stackLen = 5
+	} else if h.n < 1542 {
+		stackLen = 10
+	} else if h.n < 119151 {
+		stackLen = 19
+	}
+
+	h.runBase = make([]int, stackLen)
+	h.runLen = make([]int, stackLen)
+
+	return h
+}
+
+/**
+ * Like binarySortG, but the range lives behind an Accessor and is slid
+ * over one element at a time.
+ */
+func binarySortA[T any](a Accessor[T], lo, hi, start int, lt LessFunc[T]) {
+	if start == lo {
+		start++
+	}
+
+	for ; start < hi; start++ {
+		pivot := a.Get(start)
+
+		// Set left (and right) to the index where a[start] (pivot) belongs
+		left := lo
+		right := start
+
+		/*
+		 * Invariants:
+		 *   pivot >= all in [lo, left).
+		 *   pivot <  all in [right, start).
+		 */
+		for left < right {
+			mid := int(uint(left+right) >> 1)
+			if lt(pivot, a.Get(mid)) {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+
+		// Slide elements over to make room for pivot
+		for i := start; i > left; i-- {
+			a.Set(i, a.Get(i-1))
+		}
+		a.Set(left, pivot)
+	}
+}
+
+/**
+ * Like countRunAndMakeAscendingG, but for a range behind an Accessor.
+ */
+func countRunAndMakeAscendingA[T any](a Accessor[T], lo, hi int, lt LessFunc[T]) int {
+	runHi := lo + 1
+	if runHi == hi {
+		return 1
+	}
+
+	// Find end of run, and reverse range if descending
+	if lt(a.Get(runHi), a.Get(lo)) { // Descending
+		runHi++
+
+		for runHi < hi && lt(a.Get(runHi), a.Get(runHi-1)) {
+			runHi++
+		}
+		reverseRangeA(a, lo, runHi)
+	} else { // Ascending
+		for runHi < hi && !lt(a.Get(runHi), a.Get(runHi-1)) {
+			runHi++
+		}
+	}
+
+	return runHi - lo
+}
+
+func reverseRangeA[T any](a Accessor[T], lo, hi int) {
+	hi--
+	for lo < hi {
+		x, y := a.Get(lo), a.Get(hi)
+		a.Set(lo, y)
+		a.Set(hi, x)
+		lo++
+		hi--
+	}
+}
+
+// copyFromA copies n elements of a, starting at src, into dst
+func copyFromA[T any](dst []T, a Accessor[T], src, n int) {
+	for i := 0; i < n; i++ {
+		dst[i] = a.Get(src + i)
+	}
+}
+
+// copyToA copies the elements of src into a, starting at dst
+func copyToA[T any](a Accessor[T], dst int, src []T) {
+	for i, v := range src {
+		a.Set(dst+i, v)
+	}
+}
+
+// moveA copies n elements of a from src to dst, like copy does for
+// overlapping slices
+func moveA[T any](a Accessor[T], dst, src, n int) {
+	if dst < src {
+		for i := 0; i < n; i++ {
+			a.Set(dst+i, a.Get(src+i))
+		}
+	} else {
+		for i := n - 1; i >= 0; i-- {
+			a.Set(dst+i, a.Get(src+i))
+		}
+	}
+}
+
+func (h *timSortHandlerA[T]) pushRun(runBase, runLen int) {
+	h.runBase[h.stackSize] = runBase
+	h.runLen[h.stackSize] = runLen
+	h.stackSize++
+}
+
+// mergeCollapse is mergeCollapse of timSortHandlerG
+func (h *timSortHandlerA[T]) mergeCollapse() {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if (n > 0 && h.runLen[n-1] <= h.runLen[n]+h.runLen[n+1]) ||
+			(n > 1 && h.runLen[n-2] <= h.runLen[n-1]+h.runLen[n]) {
+			if h.runLen[n-1] < h.runLen[n+1] {
+				n--
+			}
+			h.mergeAt(n)
+		} else if h.runLen[n] <= h.runLen[n+1] {
+			h.mergeAt(n)
+		} else {
+			break // Invariant is established
+		}
+	}
+}
+
+// mergeForceCollapse is mergeForceCollapse of timSortHandlerG
+func (h *timSortHandlerA[T]) mergeForceCollapse() {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if n > 0 && h.runLen[n-1] < h.runLen[n+1] {
+			n--
+		}
+		h.mergeAt(n)
+	}
+}
+
+/**
+ * Merges the two runs at stack indices i and i+1, as in
+ * timSortHandlerG.mergeAt.
+ */
+func (h *timSortHandlerA[T]) mergeAt(i int) {
+	base1 := h.runBase[i]
+	len1 := h.runLen[i]
+	base2 := h.runBase[i+1]
+	len2 := h.runLen[i+1]
+
+	h.runLen[i] = len1 + len2
+	if i == h.stackSize-3 {
+		h.runBase[i+1] = h.runBase[i+2]
+		h.runLen[i+1] = h.runLen[i+2]
+	}
+	h.stackSize--
+
+	/*
+	 * Find where the first element of run2 goes in run1. Prior elements
+	 * in run1 can be ignored (because they're already in place).
+	 */
+	a := h.a
+	k := gallopRightA(a.Get(base2), a, base1, len1, 0, h.lt)
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+
+	/*
+	 * Find where the last element of run1 goes in run2. Subsequent elements
+	 * in run2 can be ignored (because they're already in place).
+	 */
+	len2 = gallopLeftA(a.Get(base1+len1-1), a, base2, len2, len2-1, h.lt)
+	if len2 == 0 {
+		return
+	}
+
+	// Merge remaining runs, using tmp array with min(len1, len2) elements
+	if len1 <= len2 {
+		h.mergeLo(base1, len1, base2, len2)
+	} else {
+		h.mergeHi(base1, len1, base2, len2)
+	}
+}
+
+/**
+ * Like timSortHandlerG.mergeLo: the first run is copied into tmp and
+ * merged back into a with the second run.
+ */
+func (h *timSortHandlerA[T]) mergeLo(base1, len1, base2, len2 int) {
+	// Copy first run into temp array
+	a := h.a
+	tmp := h.ensureCapacity(len1)
+
+	copyFromA(tmp, a, base1, len1)
+
+	cursor1 := 0     // Indexes into tmp array
+	cursor2 := base2 // Indexes int a
+	dest := base1    // Indexes int a
+
+	// Move first element of second run and deal with degenerate cases
+	a.Set(dest, a.Get(cursor2))
+	dest++
+	cursor2++
+	len2--
+	if len2 == 0 {
+		copyToA(a, dest, tmp[:len1])
+		return
+	}
+	if len1 == 1 {
+		moveA(a, dest, cursor2, len2)
+		a.Set(dest+len2, tmp[cursor1]) // Last elt of run 1 to end of merge
+		return
+	}
+
+	lt := h.lt               // Use local variable for performance
+	minGallop := h.minGallop //  "    "       "     "      "
+
+outer:
+	for {
+		count1 := 0 // Number of times in a row that first run won
+		count2 := 0 // Number of times in a row that second run won
+
+		/*
+		 * Do the straightforward thing until (if ever) one run starts
+		 * winning consistently.
+		 */
+		for {
+			if x := a.Get(cursor2); lt(x, tmp[cursor1]) {
+				a.Set(dest, x)
+				dest++
+				cursor2++
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 0 {
+					break outer
+				}
+			} else {
+				a.Set(dest, tmp[cursor1])
+				dest++
+				cursor1++
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+
+		/*
+		 * One run is winning so consistently that galloping may be a
+		 * huge win. So try that, and continue galloping until (if ever)
+		 * neither run appears to be winning consistently anymore.
+		 */
+		for {
+			count1 = gallopRightG(a.Get(cursor2), tmp, cursor1, len1, 0, lt)
+			if count1 != 0 {
+				copyToA(a, dest, tmp[cursor1:cursor1+count1])
+				dest += count1
+				cursor1 += count1
+				len1 -= count1
+				if len1 <= 1 { // len1 == 1 || len1 == 0
+					break outer
+				}
+			}
+			a.Set(dest, a.Get(cursor2))
+			dest++
+			cursor2++
+			len2--
+			if len2 == 0 {
+				break outer
+			}
+
+			count2 = gallopLeftA(tmp[cursor1], a, cursor2, len2, 0, lt)
+			if count2 != 0 {
+				moveA(a, dest, cursor2, count2)
+				dest += count2
+				cursor2 += count2
+				len2 -= count2
+				if len2 == 0 {
+					break outer
+				}
+			}
+			a.Set(dest, tmp[cursor1])
+			dest++
+			cursor1++
+			len1--
+			if len1 == 1 {
+				break outer
+			}
+			minGallop--
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2 // Penalize for leaving gallop mode
+	} // End of "outer" loop
+
+	if minGallop < 1 {
+		minGallop = 1
+	}
+	h.minGallop = minGallop // Write back to field
+
+	if len1 == 1 {
+		moveA(a, dest, cursor2, len2)
+		a.Set(dest+len2, tmp[cursor1]) //  Last elt of run 1 to end of merge
+	} else {
+		copyToA(a, dest, tmp[cursor1:cursor1+len1])
+	}
+}
+
+/**
+ * Like timSortHandlerG.mergeHi: the second run is copied into tmp and
+ * merged back into a with the first run, from the end.
+ */
+func (h *timSortHandlerA[T]) mergeHi(base1, len1, base2, len2 int) {
+	// Copy second run into temp array
+	a := h.a
+	tmp := h.ensureCapacity(len2)
+
+	copyFromA(tmp, a, base2, len2)
+
+	cursor1 := base1 + len1 - 1 // Indexes into a
+	cursor2 := len2 - 1         // Indexes into tmp array
+	dest := base2 + len2 - 1    // Indexes into a
+
+	// Move last element of first run and deal with degenerate cases
+	a.Set(dest, a.Get(cursor1))
+	dest--
+	cursor1--
+	len1--
+	if len1 == 0 {
+		dest -= len2 - 1
+		copyToA(a, dest, tmp[:len2])
+		return
+	}
+	if len2 == 1 {
+		dest -= len1 - 1
+		cursor1 -= len1 - 1
+		moveA(a, dest, cursor1, len1)
+		a.Set(dest-1, tmp[cursor2])
+		return
+	}
+
+	lt := h.lt               // Use local variable for performance
+	minGallop := h.minGallop //  "    "       "     "      "
+
+outer:
+	for {
+		count1 := 0 // Number of times in a row that first run won
+		count2 := 0 // Number of times in a row that second run won
+
+		/*
+		 * Do the straightforward thing until (if ever) one run
+		 * appears to win consistently.
+		 */
+		for {
+			if x := a.Get(cursor1); lt(tmp[cursor2], x) {
+				a.Set(dest, x)
+				dest--
+				cursor1--
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 0 {
+					break outer
+				}
+			} else {
+				a.Set(dest, tmp[cursor2])
+				dest--
+				cursor2--
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+
+		/*
+		 * One run is winning so consistently that galloping may be a
+		 * huge win. So try that, and continue galloping until (if ever)
+		 * neither run appears to be winning consistently anymore.
+		 */
+		for {
+			gr := gallopRightA(tmp[cursor2], a, base1, len1, len1-1, lt)
+			count1 = len1 - gr
+			if count1 != 0 {
+				dest -= count1
+				cursor1 -= count1
+				len1 -= count1
+				moveA(a, dest+1, cursor1+1, count1)
+				if len1 == 0 {
+					break outer
+				}
+			}
+			a.Set(dest, tmp[cursor2])
+			dest--
+			cursor2--
+			len2--
+			if len2 == 1 {
+				break outer
+			}
+
+			gl := gallopLeftG(a.Get(cursor1), tmp, 0, len2, len2-1, lt)
+			count2 = len2 - gl
+			if count2 != 0 {
+				dest -= count2
+				cursor2 -= count2
+				len2 -= count2
+				copyToA(a, dest+1, tmp[cursor2+1:cursor2+1+count2])
+				if len2 <= 1 { // len2 == 1 || len2 == 0
+					break outer
+				}
+			}
+			a.Set(dest, a.Get(cursor1))
+			dest--
+			cursor1--
+			len1--
+			if len1 == 0 {
+				break outer
+			}
+			minGallop--
+
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2 // Penalize for leaving gallop mode
+	} // End of "outer" loop
+
+	if minGallop < 1 {
+		minGallop = 1
+	}
+
+	h.minGallop = minGallop // Write back to field
+
+	if len2 == 1 {
+		dest -= len1
+		cursor1 -= len1
+
+		moveA(a, dest+1, cursor1+1, len1)
+		a.Set(dest, tmp[cursor2]) // Move first elt of run2 to front of merge
+	} else {
+		copyToA(a, dest-(len2-1), tmp[:len2])
+	}
+}
+
+/**
+ * Like timSortHandlerG.ensureCapacity.
+ */
+func (h *timSortHandlerA[T]) ensureCapacity(minCapacity int) []T {
+	if len(h.tmp) < minCapacity {
+		// Compute smallest power of 2 > minCapacity
+		newSize := minCapacity
+		newSize |= newSize >> 1
+		newSize |= newSize >> 2
+		newSize |= newSize >> 4
+		newSize |= newSize >> 8
+		newSize |= newSize >> 16
+		newSize++
+
+		if newSize < 0 { // Not bloody likely!
+			newSize = minCapacity
+		} else if ns := h.n / 2; ns < newSize {
+			newSize = ns
+		}
+
+		h.tmp = make([]T, newSize)
+	}
+
+	return h.tmp
+}
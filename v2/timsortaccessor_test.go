@@ -0,0 +1,106 @@
+package timsort
+
+import (
+	"slices"
+	"testing"
+)
+
+// chunked stores its elements in fixed size chunks
+type chunked[T any] struct {
+	chunks [][]T
+	size   int
+	n      int
+}
+
+func newChunked[T any](a []T, size int) *chunked[T] {
+	c := &chunked[T]{size: size, n: len(a)}
+	for len(a) > 0 {
+		k := min(size, len(a))
+		c.chunks = append(c.chunks, slices.Clone(a[:k]))
+		a = a[k:]
+	}
+	return c
+}
+
+func (c *chunked[T]) Len() int       { return c.n }
+func (c *chunked[T]) Get(i int) T    { return c.chunks[i/c.size][i%c.size] }
+func (c *chunked[T]) Set(i int, v T) { c.chunks[i/c.size][i%c.size] = v }
+
+func (c *chunked[T]) slice() []T {
+	return slices.Concat(c.chunks...)
+}
+
+// ring is a full ring buffer whose first element is at start
+type ring[T any] struct {
+	buf   []T
+	start int
+}
+
+func (r *ring[T]) Len() int       { return len(r.buf) }
+func (r *ring[T]) Get(i int) T    { return r.buf[(r.start+i)%len(r.buf)] }
+func (r *ring[T]) Set(i int, v T) { r.buf[(r.start+i)%len(r.buf)] = v }
+
+func TestSortAccessor(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 32, 100, 1000, 10000, 100000} {
+		a := makeRandomVals(size)
+		want := slices.Clone(a)
+		Slice(want, valKeyLessThan)
+
+		c := newChunked(a, 64)
+		SortAccessor[val](c, valKeyLessThan)
+		if !slices.Equal(c.slice(), want) {
+			t.Errorf("size %d: chunked not sorted stably", size)
+		}
+
+		r := &ring[val]{buf: slices.Clone(a)}
+		if size > 0 {
+			r.start = size / 3
+			// Rotate so that the logical order matches a
+			for i, v := range a {
+				r.Set(i, v)
+			}
+		}
+		SortAccessor[val](r, valKeyLessThan)
+		for i := range want {
+			if r.Get(i) != want[i] {
+				t.Errorf("size %d: ring not sorted stably at %d", size, i)
+				break
+			}
+		}
+	}
+}
+
+func TestSortAccessorComparisons(t *testing.T) {
+	// Long runs that interleave coarsely make the merges gallop, and
+	// SortAccessor should take the same steps as Slice
+	const n = 100000
+	a := make([]int, n)
+	for i := range a {
+		block := i / 5000
+		a[i] = (i%5000)*20 + block%20
+		if block%2 == 1 {
+			a[i] += 50000
+		}
+	}
+	want := slices.Clone(a)
+	slices.Sort(want)
+
+	count := 0
+	lt := func(x, y int) bool {
+		count++
+		return x < y
+	}
+	c := newChunked(a, 1000)
+	SortAccessor[int](c, lt)
+
+	if !slices.Equal(c.slice(), want) {
+		t.Fatal("not sorted")
+	}
+
+	sliceCount := count
+	count = 0
+	Slice(slices.Clone(a), lt)
+	if sliceCount != count {
+		t.Errorf("%d comparisons, Slice makes %d", sliceCount, count)
+	}
+}
@@ -0,0 +1,25 @@
+package timsort
+
+// SortComparable sorts a slice of values that compare themselves through a
+// Compare method, such as time.Time or netip.Addr.
+func SortComparable[T interface{ Compare(T) int }](a []T) {
+	Slice(a, func(a, b T) bool {
+		return a.Compare(b) < 0
+	})
+}
+
+// SortCmp sorts a slice of values that compare themselves through a Cmp
+// method, such as *big.Int, *big.Float or *big.Rat.
+func SortCmp[T interface{ Cmp(T) int }](a []T) {
+	Slice(a, func(a, b T) bool {
+		return a.Cmp(b) < 0
+	})
+}
+
+// SortLesser sorts a slice of values that order themselves through a Less
+// method, such as netip.Addr.
+func SortLesser[T interface{ Less(T) bool }](a []T) {
+	Slice(a, func(a, b T) bool {
+		return a.Less(b)
+	})
+}
@@ -0,0 +1,79 @@
+package timsort
+
+import (
+	"math/big"
+	"math/rand"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSortComparable(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := make([]time.Time, 10000)
+	for i := range a {
+		// The same instant in different locations is equal, check stability
+		loc := time.UTC
+		if i%2 == 1 {
+			loc = time.FixedZone("x", 3600)
+		}
+		a[i] = base.Add(time.Duration(rand.Intn(100)) * time.Minute).In(loc)
+	}
+	b := make([]time.Time, len(a))
+	copy(b, a)
+
+	SortComparable(a)
+	for i := 1; i < len(a); i++ {
+		if a[i].Before(a[i-1]) {
+			t.Fatalf("index %d: %v before %v", i, a[i-1], a[i])
+		}
+	}
+
+	// restore the original order of each instant
+	next := make(map[time.Time]int)
+	for _, v := range a {
+		u := v.UTC()
+		for b[next[u]].UTC() != u {
+			next[u]++
+		}
+		if b[next[u]] != v {
+			t.Fatalf("not stable at %v", v)
+		}
+		next[u]++
+	}
+}
+
+func TestSortLesser(t *testing.T) {
+	a := []netip.Addr{
+		netip.MustParseAddr("10.0.0.2"),
+		netip.MustParseAddr("::1"),
+		netip.MustParseAddr("10.0.0.10"),
+		netip.MustParseAddr("10.0.0.1"),
+	}
+	b := make([]netip.Addr, len(a))
+	copy(b, a)
+
+	SortLesser(a)
+	SortComparable(b)
+
+	want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.10", "::1"}
+	for i := range want {
+		if a[i].String() != want[i] || b[i].String() != want[i] {
+			t.Fatalf("got %v and %v, want %v", a, b, want)
+		}
+	}
+}
+
+func TestSortCmp(t *testing.T) {
+	a := make([]*big.Int, 1000)
+	for i := range a {
+		a[i] = new(big.Int).Lsh(big.NewInt(int64(rand.Intn(1000))), uint(rand.Intn(100)))
+	}
+
+	SortCmp(a)
+	for i := 1; i < len(a); i++ {
+		if a[i].Cmp(a[i-1]) < 0 {
+			t.Fatalf("index %d: %v before %v", i, a[i-1], a[i])
+		}
+	}
+}
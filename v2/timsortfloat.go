@@ -0,0 +1,99 @@
+package timsort
+
+import "math"
+
+// FloatOrder selects how the floating-point comparators order the values
+// that plain a < b does not: NaN, which compares false against everything
+// and so breaks the strict weak ordering a stable sort relies on, and
+// negative zero, which compares equal to positive zero.
+//
+// The zero FloatOrder puts NaNs before all other values and treats -0 and
+// +0 as equal.
+type FloatOrder uint
+
+const (
+	// NaNsLast puts NaNs after all other values instead of before them.
+	NaNsLast FloatOrder = 1 << iota
+
+	// NegZeroFirst orders -0 before +0 instead of treating them as equal.
+	NegZeroFirst
+)
+
+// FloatLess returns a comparator that defines a total order on float32 or
+// float64 values.  NaNs compare equal to each other, so a stable sort keeps
+// them in their original order.
+func FloatLess[F ~float32 | ~float64](o FloatOrder) LessFunc[F] {
+	nansLast := o&NaNsLast != 0
+	less := numberLess[F](o)
+
+	return func(a, b F) bool {
+		if a != a || b != b { // At least one NaN
+			if nansLast {
+				return b != b && a == a
+			}
+			return a != a && b == b
+		}
+		return less(a, b)
+	}
+}
+
+// Float64s sorts a float64 array in the total order selected by o.
+func Float64s(a []float64, o FloatOrder) {
+	sortFloats(a, o)
+}
+
+// Float32s sorts a float32 array in the total order selected by o.
+func Float32s(a []float32, o FloatOrder) {
+	sortFloats(a, o)
+}
+
+/**
+ * Moves the NaNs to the end selected by o, keeping their relative order,
+ * and then sorts the remaining numbers with a comparator that does not
+ * have to check for NaN on every call.
+ */
+func sortFloats[F ~float32 | ~float64](a []F, o FloatOrder) {
+	var nans []F
+	if o&NaNsLast != 0 {
+		n := 0
+		for _, v := range a {
+			if v != v {
+				nans = append(nans, v)
+			} else {
+				a[n] = v
+				n++
+			}
+		}
+		copy(a[n:], nans)
+		a = a[:n]
+	} else {
+		n := len(a)
+		for i := len(a) - 1; i >= 0; i-- {
+			if v := a[i]; v != v {
+				nans = append(nans, v)
+			} else {
+				n--
+				a[n] = v
+			}
+		}
+		reverseRangeG(nans, 0, len(nans))
+		copy(a, nans)
+		a = a[n:]
+	}
+
+	Slice(a, numberLess[F](o))
+}
+
+func numberLess[F ~float32 | ~float64](o FloatOrder) LessFunc[F] {
+	if o&NegZeroFirst == 0 {
+		return func(a, b F) bool {
+			return a < b
+		}
+	}
+	return func(a, b F) bool {
+		if a == 0 && b == 0 {
+			return math.Signbit(float64(a)) && !math.Signbit(float64(b))
+		}
+		return a < b
+	}
+}
@@ -0,0 +1,105 @@
+package timsort
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func makeSensorData(size int) []float64 {
+	negZero := math.Copysign(0, -1)
+	special := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 0, negZero}
+	a := make([]float64, size)
+
+	for i := range a {
+		if rand.Intn(4) == 0 {
+			a[i] = special[rand.Intn(len(special))]
+		} else {
+			a[i] = float64(rand.Intn(100) - 50)
+		}
+	}
+
+	return a
+}
+
+func checkFloats(t *testing.T, a []float64, o FloatOrder) {
+	t.Helper()
+	nans := 0
+	for _, v := range a {
+		if math.IsNaN(v) {
+			nans++
+		}
+	}
+
+	nums := a[nans:]
+	if o&NaNsLast != 0 {
+		nums = a[:len(a)-nans]
+	}
+	for i, v := range nums {
+		if math.IsNaN(v) {
+			t.Fatalf("o=%d: NaN at %d among numbers", o, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := nums[i-1]
+		if v < prev {
+			t.Fatalf("o=%d: %v before %v", o, prev, v)
+		}
+		if o&NegZeroFirst != 0 && v == 0 && prev == 0 && math.Signbit(v) && !math.Signbit(prev) {
+			t.Fatalf("o=%d: +0 before -0", o)
+		}
+	}
+}
+
+func TestFloat64s(t *testing.T) {
+	for _, o := range []FloatOrder{0, NaNsLast, NegZeroFirst, NaNsLast | NegZeroFirst} {
+		for _, size := range []int{0, 1, 31, 1024, 100 * 1024} {
+			a := makeSensorData(size)
+			Float64s(a, o)
+			checkFloats(t, a, o)
+
+			b := makeSensorData(size)
+			Slice(b, FloatLess[float64](o))
+			checkFloats(t, b, o)
+		}
+	}
+}
+
+func TestFloat32s(t *testing.T) {
+	a := []float32{3, float32(math.NaN()), -1, float32(math.Copysign(0, -1)), 0, float32(math.Inf(-1))}
+
+	Float32s(a, NaNsLast|NegZeroFirst)
+	if a[0] != float32(math.Inf(-1)) || a[1] != -1 || !math.Signbit(float64(a[2])) ||
+		a[3] != 0 || math.Signbit(float64(a[3])) || a[4] != 3 || a[5] == a[5] {
+		t.Errorf("got %v", a)
+	}
+}
+
+func TestFloatLessStable(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	type reading struct {
+		v     float64
+		order int
+	}
+	a := make([]reading, 10000)
+	for i, v := range makeSensorData(len(a)) {
+		a[i] = reading{v, i}
+	}
+	b := make([]reading, len(a))
+	copy(b, a)
+
+	less := FloatLess[float64](NaNsLast)
+	Slice(a, func(x, y reading) bool { return less(x.v, y.v) })
+	sort.SliceStable(b, func(i, j int) bool { return less(b[i].v, b[j].v) })
+	for i := range a {
+		if a[i].order != b[i].order {
+			t.Fatalf("index %d: got %v, want %v", i, a[i], b[i])
+		}
+	}
+
+	if less(negZero, 0) || less(0, negZero) {
+		t.Error("-0 and +0 should compare equal")
+	}
+}
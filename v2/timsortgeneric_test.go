@@ -0,0 +1,87 @@
+package timsort
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func valKeyLessThan(a, b val) bool {
+	return a.key < b.key
+}
+
+func makeRandomVals(size int) []val {
+	a := make([]val, size)
+
+	for i := 0; i < size; i++ {
+		a[i] = val{rand.Intn(100), i}
+	}
+
+	return a
+}
+
+func isSortedVals(a []val, lessThan LessFunc[val]) bool {
+	for i := 1; i < len(a); i++ {
+		if lessThan(a[i], a[i-1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// use this comparator to validate sorted data (and prove its stable)
+func valKeyOrderLessThan(a, b val) bool {
+	return a.key < b.key || a.key == b.key && a.order < b.order
+}
+
+func valKeyDescOrderLessThan(a, b val) bool {
+	return a.key > b.key || a.key == b.key && a.order < b.order
+}
+
+func TestSlice(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 100, 1024, 1024 * 1024} {
+		a := makeRandomVals(size)
+
+		Slice(a, valKeyLessThan)
+		if !isSortedVals(a, valKeyOrderLessThan) {
+			t.Errorf("size=%d: not sorted", size)
+		}
+	}
+}
+
+func TestSliceDescending(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 100, 1024, 100 * 1024} {
+		a := makeRandomVals(size)
+
+		SliceDescending(a, valKeyLessThan)
+		if !isSortedVals(a, valKeyDescOrderLessThan) {
+			t.Errorf("size=%d: not sorted", size)
+		}
+	}
+}
+
+func TestSliceDescendingPresorted(t *testing.T) {
+	size := 100 * 1024
+	for _, desc := range []bool{false, true} {
+		a := make([]val, size)
+		for i := 0; i < size; i++ {
+			key := i / 3
+			if desc {
+				key = size - i/3
+			}
+			a[i] = val{key, i}
+		}
+
+		compares := 0
+		SliceDescending(a, func(a, b val) bool {
+			compares++
+			return a.key < b.key
+		})
+		if !isSortedVals(a, valKeyDescOrderLessThan) {
+			t.Errorf("desc=%v: not sorted", desc)
+		}
+		if compares > 3*size {
+			t.Errorf("desc=%v: %d compares for %d elements", desc, compares, size)
+		}
+	}
+}
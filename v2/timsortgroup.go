@@ -0,0 +1,61 @@
+package timsort
+
+import "iter"
+
+// Keep selects which element of a group of equal elements Compact keeps.
+type Keep int
+
+const (
+	// KeepFirst keeps the first element of each group.  After a stable
+	// sort that is the element that came first in the original order.
+	KeepFirst Keep = iota
+
+	// KeepLast keeps the last element of each group, such as the most
+	// recent update when records were appended over time.
+	KeepLast
+)
+
+// GroupBy returns an iterator over the groups of consecutive equal elements
+// of a, typically a sorted slice.  Each group is yielded as the half-open
+// range of indexes [start, end).
+func GroupBy[T any](a []T, equal func(a, b T) bool) iter.Seq2[int, int] {
+	return func(yield func(start, end int) bool) {
+		for start := 0; start < len(a); {
+			end := start + 1
+			for end < len(a) && equal(a[start], a[end]) {
+				end++
+			}
+			if !yield(start, end) {
+				return
+			}
+			start = end
+		}
+	}
+}
+
+// Compact replaces each group of consecutive equal elements of a by a single
+// element, chosen by keep, and returns the shortened slice.  The elements
+// between the new length and the old one are zeroed.
+func Compact[T any](a []T, equal func(a, b T) bool, keep Keep) []T {
+	n := 0
+	for start, end := range GroupBy(a, equal) {
+		if keep == KeepLast {
+			a[n] = a[end-1]
+		} else {
+			a[n] = a[start]
+		}
+		n++
+	}
+
+	clear(a[n:])
+	return a[:n]
+}
+
+// SortUnique sorts a and removes all but the first of equal elements, in
+// their original order, and returns the shortened slice.
+func SortUnique[T any](a []T, lt LessFunc[T]) []T {
+	Slice(a, lt)
+	return Compact(a, func(x, y T) bool {
+		return !lt(x, y) // Sorted, so y is never less than x
+	}, KeepFirst)
+}
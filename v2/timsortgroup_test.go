@@ -0,0 +1,73 @@
+package timsort
+
+import (
+	"slices"
+	"testing"
+)
+
+func valKeyEqual(a, b val) bool {
+	return a.key == b.key
+}
+
+func TestGroupBy(t *testing.T) {
+	a := []val{{1, 0}, {1, 1}, {2, 2}, {3, 3}, {3, 4}, {3, 5}}
+
+	var got [][2]int
+	for start, end := range GroupBy(a, valKeyEqual) {
+		got = append(got, [2]int{start, end})
+	}
+	want := [][2]int{{0, 2}, {2, 3}, {3, 6}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for start, end := range GroupBy(a, valKeyEqual) {
+		if start != 0 || end != 2 {
+			t.Errorf("got [%d, %d)", start, end)
+		}
+		break
+	}
+
+	for range GroupBy([]val{}, valKeyEqual) {
+		t.Error("group in empty slice")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	a := []val{{1, 0}, {1, 1}, {2, 2}, {3, 3}, {3, 4}, {3, 5}}
+
+	got := Compact(slices.Clone(a), valKeyEqual, KeepFirst)
+	if want := []val{{1, 0}, {2, 2}, {3, 3}}; !slices.Equal(got, want) {
+		t.Errorf("keep first: got %v, want %v", got, want)
+	}
+
+	b := slices.Clone(a)
+	got = Compact(b, valKeyEqual, KeepLast)
+	if want := []val{{1, 1}, {2, 2}, {3, 5}}; !slices.Equal(got, want) {
+		t.Errorf("keep last: got %v, want %v", got, want)
+	}
+	if b[3] != (val{}) || b[5] != (val{}) {
+		t.Errorf("tail not cleared: %v", b)
+	}
+}
+
+func TestSortUnique(t *testing.T) {
+	a := makeRandomVals(10000)
+	first := make(map[int]int)
+	for i := len(a) - 1; i >= 0; i-- {
+		first[a[i].key] = a[i].order
+	}
+
+	got := SortUnique(a, valKeyLessThan)
+	if len(got) != 100 {
+		t.Fatalf("got %d unique keys, want 100", len(got))
+	}
+	for i, v := range got {
+		if v.key != i {
+			t.Fatalf("index %d: got key %d", i, v.key)
+		}
+		if v.order != first[v.key] {
+			t.Fatalf("index %d: not the first occurrence", i)
+		}
+	}
+}
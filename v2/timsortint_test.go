@@ -3,6 +3,7 @@ package timsort
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"testing"
 )
 
@@ -116,3 +117,32 @@ func TestRandom1MI(t *testing.T) {
 		t.Error("not sorted")
 	}
 }
+
+func TestIntsDescending(t *testing.T) {
+	a := makeRandomArrayI(100 * 1024)
+
+	IntsDescending(a, intLessThan)
+	if !sort.IsSorted(sort.Reverse(sort.IntSlice(a))) {
+		t.Error("not sorted")
+	}
+}
+
+func TestIntsDescendingPresorted(t *testing.T) {
+	size := 100 * 1024
+	a := make([]int, size)
+	for i := 0; i < size; i++ {
+		a[i] = i / 3
+	}
+
+	compares := 0
+	IntsDescending(a, func(a, b int) bool {
+		compares++
+		return a < b
+	})
+	if !sort.IsSorted(sort.Reverse(sort.IntSlice(a))) {
+		t.Error("not sorted")
+	}
+	if compares > 3*size {
+		t.Errorf("%d compares for %d elements", compares, size)
+	}
+}
@@ -0,0 +1,99 @@
+package timsort
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Sorted collects the elements of seq, sorts them stably and returns an
+// iterator over the result.  seq is consumed when the iteration starts.
+func Sorted[T any](seq iter.Seq[T], lt LessFunc[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var a []T
+		for v := range seq {
+			a = append(a, v)
+		}
+		Slice(a, lt)
+
+		for _, v := range a {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SortedKeys returns an iterator over the keys of m in ascending order.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) iter.Seq[K] {
+	return SortedKeysFunc(m, cmp.Less[K])
+}
+
+// SortedKeysFunc returns an iterator over the keys of m, sorted by lt.
+func SortedKeysFunc[K comparable, V any](m map[K]V, lt LessFunc[K]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys := make([]K, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		Slice(keys, lt)
+
+		for _, k := range keys {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// MergeSeq returns an iterator over the stable merge of the sorted
+// sequences seqs, see MergeKSeq.  The sequences are consumed lazily, only
+// as far as the consumer iterates, and are stopped when it breaks out of
+// the loop.
+func MergeSeq[T any](lt LessFunc[T], seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		MergeKSeq(seqs, lt, yield)
+	}
+}
+
+// LazySorted returns an iterator over the elements of a in the order Slice
+// would put them, doing only the work the consumer pulls.  When the
+// iteration starts, it finds the natural runs of a, extending short ones
+// to a minimum length, as Slice does; from then on the runs are merged on
+// demand with a tournament tree.  Reading the first k elements takes about
+// O(n + k log n) comparisons.
+//
+// LazySorted rearranges the elements of a within their runs, and a must
+// not be modified during the iteration.
+func LazySorted[T any](a []T, lt LessFunc[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		MergeKFunc(findRuns(a, lt), lt, yield)
+	}
+}
+
+/**
+ * Splits a into ascending runs the way Slice does, reversing descending
+ * runs and extending short ones with binarySort, but without merging them.
+ */
+func findRuns[T any](a []T, lt LessFunc[T]) [][]T {
+	n := len(a)
+	if n < 2 {
+		return [][]T{a}
+	}
+
+	var runs [][]T
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		runLen := countRunAndMakeAscendingG(a, lo, n, lt)
+
+		// If run is short, extend to min(minRun, nRemaining)
+		if runLen < minRun {
+			force := min(minRun, n-lo)
+			binarySortG(a, lo, lo+force, lo+runLen, lt)
+			runLen = force
+		}
+
+		runs = append(runs, a[lo:lo+runLen])
+		lo += runLen
+	}
+	return runs
+}
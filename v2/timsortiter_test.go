@@ -0,0 +1,118 @@
+package timsort
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestSorted(t *testing.T) {
+	a := makeRandomVals(10000)
+	want := slices.Clone(a)
+	Slice(want, valKeyLessThan)
+
+	if got := slices.Collect(Sorted(slices.Values(a), valKeyLessThan)); !slices.Equal(got, want) {
+		t.Error("not sorted")
+	}
+
+	for v := range Sorted(slices.Values(a), valKeyLessThan) {
+		if v != want[0] {
+			t.Errorf("got %v, want %v", v, want[0])
+		}
+		break
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	m := map[string]int{"b": 1, "c": 2, "a": 3}
+
+	if got := slices.Collect(SortedKeys(m)); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("got %v", got)
+	}
+
+	desc := func(a, b string) bool { return a > b }
+	if got := slices.Collect(SortedKeysFunc(m, desc)); !slices.Equal(got, []string{"c", "b", "a"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+// countingSeq yields the elements of a and counts how many were pulled and
+// whether the sequence was left early
+func countingSeq(a []int, pulled *int, stopped *bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, v := range a {
+			*pulled++
+			if !yield(v) {
+				*stopped = true
+				return
+			}
+		}
+	}
+}
+
+func TestMergeSeq(t *testing.T) {
+	a := []int{1, 4, 7, 10}
+	b := []int{2, 5, 8}
+	c := []int{3, 6, 9, 11, 12}
+
+	var pulled [3]int
+	var stopped [3]bool
+	seqs := []iter.Seq[int]{
+		countingSeq(a, &pulled[0], &stopped[0]),
+		countingSeq(b, &pulled[1], &stopped[1]),
+		countingSeq(c, &pulled[2], &stopped[2]),
+	}
+
+	got := slices.Collect(MergeSeq(intLessThan, seqs...))
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	pulled = [3]int{}
+	for v := range MergeSeq(intLessThan, seqs...) {
+		if v == 3 {
+			break
+		}
+	}
+	if pulled != [3]int{2, 2, 1} || stopped != [3]bool{true, true, true} {
+		t.Errorf("pulled %v, stopped %v", pulled, stopped)
+	}
+}
+
+func TestLazySorted(t *testing.T) {
+	for _, size := range []int{0, 1, 31, 1000, 100 * 1024} {
+		a := makeRandomVals(size)
+		want := slices.Clone(a)
+		Slice(want, valKeyLessThan)
+
+		if got := slices.Collect(LazySorted(a, valKeyLessThan)); !slices.Equal(got, want) {
+			t.Fatalf("size=%d: not sorted", size)
+		}
+	}
+}
+
+func TestLazySortedCompares(t *testing.T) {
+	size := 1024 * 1024
+	a := makeRandomArrayI(size)
+	want := slices.Clone(a)
+	slices.Sort(want)
+
+	compares := 0
+	var got []int
+	for v := range LazySorted(a, func(x, y int) bool {
+		compares++
+		return x < y
+	}) {
+		got = append(got, v)
+		if len(got) == 10 {
+			break
+		}
+	}
+
+	if !slices.Equal(got, want[:10]) {
+		t.Fatalf("got %v, want %v", got, want[:10])
+	}
+	if compares > 8*size {
+		t.Errorf("%d compares for the first 10 of %d", compares, size)
+	}
+}
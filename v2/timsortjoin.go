@@ -0,0 +1,127 @@
+package timsort
+
+// JoinKind selects which unmatched records MergeJoin reports.
+type JoinKind int
+
+const (
+	// InnerJoin reports only pairs of records with equal keys.
+	InnerJoin JoinKind = iota
+
+	// LeftJoin also reports left records without a match, paired with nil.
+	LeftJoin
+
+	// RightJoin also reports right records without a match, paired with nil.
+	RightJoin
+
+	// FullJoin reports unmatched records of both sides.
+	FullJoin
+)
+
+// MergeJoin joins two slices that are sorted by key and calls yield for
+// every resulting pair, in key order.  Records with equal keys on both sides
+// produce their cross product, in the order of left then right records.  For
+// outer joins an unmatched record is paired with nil.  MergeJoin stops early
+// when yield returns false.
+//
+// Stretches of one side without a counterpart on the other are skipped by
+// galloping, so an inner join of a few records against many takes only a
+// logarithmic number of key comparisons per match.
+func MergeJoin[L, R, K any](left []L, right []R, leftKey func(L) K, rightKey func(R) K, lt LessFunc[K], kind JoinKind, yield func(l *L, r *R) bool) {
+	keepLeft := kind == LeftJoin || kind == FullJoin
+	keepRight := kind == RightJoin || kind == FullJoin
+
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		kl, kr := leftKey(left[i]), rightKey(right[j])
+
+		if lt(kl, kr) {
+			end := gallopIndex(i, len(left), func(x int) bool { return lt(leftKey(left[x]), kr) })
+			if !keepLeft {
+				i = end
+				continue
+			}
+			for ; i < end; i++ {
+				if !yield(&left[i], nil) {
+					return
+				}
+			}
+			continue
+		}
+
+		if lt(kr, kl) {
+			end := gallopIndex(j, len(right), func(x int) bool { return lt(rightKey(right[x]), kl) })
+			if !keepRight {
+				j = end
+				continue
+			}
+			for ; j < end; j++ {
+				if !yield(nil, &right[j]) {
+					return
+				}
+			}
+			continue
+		}
+
+		// Keys are equal, join both groups of equal keys
+		endL := gallopIndex(i, len(left), func(x int) bool { return !lt(kl, leftKey(left[x])) })
+		endR := gallopIndex(j, len(right), func(x int) bool { return !lt(kl, rightKey(right[x])) })
+		for ; i < endL; i++ {
+			for k := j; k < endR; k++ {
+				if !yield(&left[i], &right[k]) {
+					return
+				}
+			}
+		}
+		j = endR
+	}
+
+	for ; keepLeft && i < len(left); i++ {
+		if !yield(&left[i], nil) {
+			return
+		}
+	}
+	for ; keepRight && j < len(right); j++ {
+		if !yield(nil, &right[j]) {
+			return
+		}
+	}
+}
+
+/**
+ * Returns the first index in [lo, hi) for which before is false, or hi if
+ * there is none, assuming that before holds for a prefix of the range.
+ * Like gallopLeft, it probes lo, lo+1, lo+3, lo+7, ... before doing a
+ * binary search, so the cost is logarithmic in the distance from lo
+ * rather than in the length of the range.
+ */
+func gallopIndex(lo, hi int, before func(i int) bool) int {
+	if lo >= hi || !before(lo) {
+		return lo
+	}
+
+	// Gallop right until before(lo+lastOfs) && !before(lo+ofs)
+	lastOfs := 0
+	ofs := 1
+	for lo+ofs < hi && before(lo+ofs) {
+		lastOfs = ofs
+		ofs = (ofs << 1) + 1
+		if ofs <= 0 { // int overflow
+			ofs = hi - lo
+		}
+	}
+	if ofs > hi-lo {
+		ofs = hi - lo
+	}
+
+	// Binary search, with invariant before(left-1) && !before(right)
+	left, right := lo+lastOfs+1, lo+ofs
+	for left < right {
+		mid := int(uint(left+right) >> 1)
+		if before(mid) {
+			left = mid + 1
+		} else {
+			right = mid
+		}
+	}
+	return left
+}
@@ -0,0 +1,122 @@
+package timsort
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type order struct {
+	customer int
+	amount   int
+}
+
+type customer struct {
+	id   int
+	name string
+}
+
+func orderKey(o order) int       { return o.customer }
+func customerKey(c customer) int { return c.id }
+
+// joinReference joins with nested loops, in the order MergeJoin reports
+func joinReference(orders []order, customers []customer, kind JoinKind) []string {
+	var res []string
+	pair := func(o *order, c *customer) string { return fmt.Sprint(o, c) }
+
+	// Walk keys in order; unmatched records sort by their own key
+	for oi, ci := 0, 0; oi < len(orders) || ci < len(customers); {
+		switch {
+		case ci == len(customers) || oi < len(orders) && orders[oi].customer < customers[ci].id:
+			if kind == LeftJoin || kind == FullJoin {
+				res = append(res, pair(&orders[oi], nil))
+			}
+			oi++
+		case oi == len(orders) || customers[ci].id < orders[oi].customer:
+			if kind == RightJoin || kind == FullJoin {
+				res = append(res, pair(nil, &customers[ci]))
+			}
+			ci++
+		default:
+			key := orders[oi].customer
+			for ; oi < len(orders) && orders[oi].customer == key; oi++ {
+				for k := ci; k < len(customers) && customers[k].id == key; k++ {
+					res = append(res, pair(&orders[oi], &customers[k]))
+				}
+			}
+			for ; ci < len(customers) && customers[ci].id == key; ci++ {
+			}
+		}
+	}
+	return res
+}
+
+func TestMergeJoin(t *testing.T) {
+	for _, kind := range []JoinKind{InnerJoin, LeftJoin, RightJoin, FullJoin} {
+		for _, keys := range []int{2, 20, 1000} {
+			orders := make([]order, rand.Intn(200))
+			for i := range orders {
+				orders[i] = order{rand.Intn(keys), i}
+			}
+			customers := make([]customer, rand.Intn(100))
+			for i := range customers {
+				customers[i] = customer{rand.Intn(keys), fmt.Sprint("c", i)}
+			}
+			Slice(orders, func(a, b order) bool { return a.customer < b.customer })
+			Slice(customers, func(a, b customer) bool { return a.id < b.id })
+
+			var got []string
+			MergeJoin(orders, customers, orderKey, customerKey, intLessThan, kind, func(o *order, c *customer) bool {
+				got = append(got, fmt.Sprint(o, c))
+				return true
+			})
+
+			want := joinReference(orders, customers, kind)
+			if !slices.Equal(got, want) {
+				t.Fatalf("kind=%d keys=%d:\ngot  %v\nwant %v", kind, keys, got, want)
+			}
+		}
+	}
+}
+
+func TestMergeJoinSkips(t *testing.T) {
+	size := 1024 * 1024
+	customers := make([]customer, size)
+	for i := range customers {
+		customers[i] = customer{i, ""}
+	}
+	orders := []order{{5, 1}, {5, 2}, {70000, 3}, {size + 1, 4}}
+
+	compares := 0
+	pairs := 0
+	MergeJoin(orders, customers, orderKey, customerKey, func(a, b int) bool {
+		compares++
+		return a < b
+	}, InnerJoin, func(o *order, c *customer) bool {
+		if o.customer != c.id {
+			t.Errorf("%v joined with %v", o, c)
+		}
+		pairs++
+		return true
+	})
+	if pairs != 3 {
+		t.Errorf("got %d pairs, want 3", pairs)
+	}
+	if compares > 500 {
+		t.Errorf("%d compares for joining %d with %d records", compares, len(orders), size)
+	}
+}
+
+func TestGallopIndex(t *testing.T) {
+	for n := 0; n < 70; n++ {
+		for lo := 0; lo <= n; lo++ {
+			for want := lo; want <= n; want++ {
+				got := gallopIndex(lo, n, func(i int) bool { return i < want })
+				if got != want {
+					t.Fatalf("n=%d lo=%d: got %d, want %d", n, lo, got, want)
+				}
+			}
+		}
+	}
+}
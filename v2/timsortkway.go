@@ -0,0 +1,202 @@
+package timsort
+
+import "iter"
+
+// MergeK returns the stable merge of the sorted slices in runs.  Equal
+// elements keep the order of the slices they come from.
+//
+// The slices are merged in a single pass with a tournament (loser) tree,
+// which takes about log2(len(runs)) comparisons per element.  When one
+// slice keeps winning, the merge gallops through it and copies whole
+// stretches at once.
+func MergeK[T any](runs [][]T, lt LessFunc[T]) []T {
+	n := 0
+	for _, run := range runs {
+		n += len(run)
+	}
+
+	merged := make([]T, 0, n)
+	MergeKFunc(runs, lt, func(v T) bool {
+		merged = append(merged, v)
+		return true
+	})
+	return merged
+}
+
+// MergeKFunc is like MergeK, except that it streams the merged elements to
+// yield instead of collecting them.  It stops early when yield returns
+// false.
+func MergeKFunc[T any](runs [][]T, lt LessFunc[T], yield func(T) bool) {
+	if len(runs) == 0 {
+		return
+	}
+
+	pos := make([]int, len(runs)) // Index of the head of each run
+	tree := newLoserTree(len(runs), func(i, j int) bool {
+		if pos[i] >= len(runs[i]) {
+			return false
+		}
+		if pos[j] >= len(runs[j]) {
+			return true
+		}
+		a, b := runs[i][pos[i]], runs[j][pos[j]]
+		return lt(a, b) || (i < j && !lt(b, a))
+	})
+
+	last := -1 // Run that won last time
+	wins := 0  // Number of times in a row that it won
+	for {
+		w := tree.winner()
+		run := runs[w]
+		if pos[w] >= len(run) {
+			return // All runs are exhausted
+		}
+
+		if w == last {
+			wins++
+		} else {
+			last, wins = w, 1
+		}
+
+		if wins < minGallop {
+			if !yield(run[pos[w]]) {
+				return
+			}
+			pos[w]++
+		} else {
+			/*
+			 * One run is winning so consistently that galloping may be a
+			 * huge win.  Everything in it up to the head of the runner-up
+			 * can be emitted without consulting the tree; the runner-up
+			 * is the best of the runs that lost to w on its way up.
+			 */
+			end := len(run)
+			if r := tree.runnerUp(); r >= 0 && pos[r] < len(runs[r]) {
+				key := runs[r][pos[r]]
+				if w < r {
+					end = pos[w] + gallopRightG(key, run, pos[w], len(run)-pos[w], 0, lt)
+				} else {
+					end = pos[w] + gallopLeftG(key, run, pos[w], len(run)-pos[w], 0, lt)
+				}
+			}
+			if end-pos[w] < minGallop {
+				wins = 0 // Penalize for leaving gallop mode
+			}
+			for ; pos[w] < end; pos[w]++ {
+				if !yield(run[pos[w]]) {
+					return
+				}
+			}
+		}
+
+		tree.replay(w)
+	}
+}
+
+// MergeKSeq is like MergeKFunc, but merges sorted sequences.  The sequences
+// are consumed lazily, one element at a time, and are stopped when the
+// merge returns.
+func MergeKSeq[T any](seqs []iter.Seq[T], lt LessFunc[T], yield func(T) bool) {
+	if len(seqs) == 0 {
+		return
+	}
+
+	next := make([]func() (T, bool), len(seqs))
+	heads := make([]T, len(seqs))
+	ok := make([]bool, len(seqs)) // Whether a sequence has a head
+	for i, seq := range seqs {
+		var stop func()
+		next[i], stop = iter.Pull(seq)
+		defer stop()
+		heads[i], ok[i] = next[i]()
+	}
+
+	tree := newLoserTree(len(seqs), func(i, j int) bool {
+		if !ok[i] {
+			return false
+		}
+		if !ok[j] {
+			return true
+		}
+		return lt(heads[i], heads[j]) || (i < j && !lt(heads[j], heads[i]))
+	})
+
+	for {
+		w := tree.winner()
+		if !ok[w] {
+			return // All sequences are exhausted
+		}
+		if !yield(heads[w]) {
+			return
+		}
+		heads[w], ok[w] = next[w]()
+		tree.replay(w)
+	}
+}
+
+/**
+ * A tournament tree over k sources that keeps the loser of every match in
+ * the inner nodes, so that after the winner's head changes only the matches
+ * on its path to the root have to be replayed.  Sources are identified by
+ * index and compared through less, which must order exhausted sources last
+ * and break ties by index to keep merges stable.
+ *
+ * The tree is laid out like a heap: node 0 holds the overall winner, nodes
+ * 1 to k-1 the losers, and source i is the leaf at node k+i.
+ */
+type loserTree struct {
+	k    int
+	tree []int
+	less func(i, j int) bool
+}
+
+func newLoserTree(k int, less func(i, j int) bool) *loserTree {
+	t := &loserTree{
+		k:    k,
+		tree: make([]int, k),
+		less: less}
+	t.tree[0] = t.build(1)
+	return t
+}
+
+// build plays the matches below node and returns the winner
+func (t *loserTree) build(node int) int {
+	if node >= t.k {
+		return node - t.k
+	}
+
+	l := t.build(2 * node)
+	r := t.build(2*node + 1)
+	if t.less(r, l) {
+		t.tree[node] = l
+		return r
+	}
+	t.tree[node] = r
+	return l
+}
+
+func (t *loserTree) winner() int {
+	return t.tree[0]
+}
+
+// replay replays the matches of source i on its path to the root
+func (t *loserTree) replay(i int) {
+	for node := (i + t.k) / 2; node > 0; node /= 2 {
+		if t.less(t.tree[node], i) {
+			t.tree[node], i = i, t.tree[node]
+		}
+	}
+	t.tree[0] = i
+}
+
+// runnerUp returns the source that would win if the winner were removed,
+// or -1 if there is only one source
+func (t *loserTree) runnerUp() int {
+	r := -1
+	for node := (t.tree[0] + t.k) / 2; node > 0; node /= 2 {
+		if r < 0 || t.less(t.tree[node], r) {
+			r = t.tree[node]
+		}
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+package timsort
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func makeShards(k, maxSize, keys int) [][]val {
+	runs := make([][]val, k)
+	order := 0
+	for i := range runs {
+		runs[i] = makeSortedVals(rand.Intn(maxSize+1), keys, order)
+		order += len(runs[i])
+	}
+	return runs
+}
+
+func TestMergeK(t *testing.T) {
+	for _, k := range []int{0, 1, 2, 3, 7, 64, 300} {
+		for _, keys := range []int{1, 10, 100000} {
+			runs := makeShards(k, 200, keys)
+
+			merged := MergeK(runs, valKeyLessThan)
+			n := 0
+			for _, run := range runs {
+				n += len(run)
+			}
+			if len(merged) != n {
+				t.Fatalf("k=%d: got %d elements, want %d", k, len(merged), n)
+			}
+			if !isSortedVals(merged, valKeyOrderLessThan) {
+				t.Fatalf("k=%d keys=%d: not merged", k, keys)
+			}
+		}
+	}
+}
+
+func TestMergeKGallops(t *testing.T) {
+	// one big run dominates a few small ones
+	size := 100 * 1024
+	big := make([]int, size)
+	for i := range big {
+		big[i] = i
+	}
+	runs := [][]int{{-5, size + 1}, big, {size / 2}, {}}
+
+	compares := 0
+	merged := MergeK(runs, func(a, b int) bool {
+		compares++
+		return a < b
+	})
+	if !slices.IsSorted(merged) || len(merged) != size+3 {
+		t.Fatal("not merged")
+	}
+	if compares > size/10 {
+		t.Errorf("%d compares for %d elements", compares, len(merged))
+	}
+}
+
+func TestMergeKFuncStop(t *testing.T) {
+	runs := makeShards(10, 100, 50)
+
+	var got []val
+	MergeKFunc(runs, valKeyLessThan, func(v val) bool {
+		got = append(got, v)
+		return len(got) < 25
+	})
+
+	want := MergeK(runs, valKeyLessThan)
+	if len(want) > 25 {
+		want = want[:25]
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMergeKSeq(t *testing.T) {
+	runs := makeShards(50, 100, 20)
+
+	seqs := make([]iter.Seq[val], len(runs))
+	for i, run := range runs {
+		seqs[i] = slices.Values(run)
+	}
+
+	var got []val
+	MergeKSeq(seqs, valKeyLessThan, func(v val) bool {
+		got = append(got, v)
+		return true
+	})
+	if !slices.Equal(got, MergeK(runs, valKeyLessThan)) {
+		t.Error("sequence merge differs from slice merge")
+	}
+
+	n := 0
+	MergeKSeq(seqs, valKeyLessThan, func(v val) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("did not stop early: %d", n)
+	}
+}
@@ -0,0 +1,207 @@
+package timsort
+
+import (
+	"container/list"
+)
+
+// SortList sorts the elements of l in place by relinking its nodes; no
+// element is copied and no node is allocated.  The sort is stable.
+//
+// As with Sort, natural runs are found (strictly descending runs are
+// reversed), short runs are extended to minRun elements by insertion, and
+// runs are merged pairwise under the timsort stack invariant.  A list that
+// is already sorted, or sorted in reverse, is handled in linear time.
+func SortList(l *list.List, lt LessThan) {
+	n := l.Len()
+	if n < 2 {
+		return
+	}
+
+	ls := &listSorter{l: l, lt: lt}
+	minRun := n
+	if n >= minMerge {
+		minRun = minRunLength(n)
+	}
+
+	e := l.Front()
+	for nRemaining := n; nRemaining > 0; {
+		head, runLen := ls.countRunAndMakeAscending(e, nRemaining)
+
+		// If run is short, extend to min(minRun, nRemaining)
+		if runLen < minRun {
+			force := minRun
+			if nRemaining <= minRun {
+				force = nRemaining
+			}
+			head = ls.insertionSort(head, runLen, force)
+			runLen = force
+		}
+
+		// Find the start of the next run before merging moves any nodes
+		e = head
+		for i := 0; i < runLen; i++ {
+			e = e.Next()
+		}
+
+		ls.pushRun(head, runLen)
+		ls.mergeCollapse()
+		nRemaining -= runLen
+	}
+
+	ls.mergeForceCollapse()
+}
+
+// listSorter holds the stack of pending runs of a SortList call.  A run
+// is identified by its first node and its length; merging two adjacent
+// runs only moves nodes within them, so the heads of later runs stay valid.
+type listSorter struct {
+	l  *list.List
+	lt LessThan
+
+	runHead []*list.Element
+	runLen  []int
+}
+
+/**
+ * Returns the length of the run beginning at head, and reverses it if it
+ * is descending.  Like countRunAndMakeAscending, but on a list; the first
+ * node of the, possibly reversed, run is returned too.
+ *
+ * @param head the first node of the run
+ * @param n the number of nodes from head to the end of the list, >= 1
+ */
+func (ls *listSorter) countRunAndMakeAscending(head *list.Element, n int) (*list.Element, int) {
+	if n == 1 {
+		return head, 1
+	}
+
+	runLen := 2
+	e := head.Next()
+	if ls.lt(e.Value, head.Value) { // Descending
+		for runLen < n && ls.lt(e.Next().Value, e.Value) {
+			e = e.Next()
+			runLen++
+		}
+		return ls.reverse(head, runLen), runLen
+	}
+
+	for runLen < n && !ls.lt(e.Next().Value, e.Value) { // Ascending
+		e = e.Next()
+		runLen++
+	}
+	return head, runLen
+}
+
+// reverse reverses the n nodes starting at head and returns the new head
+func (ls *listSorter) reverse(head *list.Element, n int) *list.Element {
+	first := head
+	e := head.Next()
+	for i := 1; i < n; i++ {
+		next := e.Next()
+		ls.l.MoveBefore(e, first)
+		first = e
+		e = next
+	}
+	return first
+}
+
+/**
+ * Sorts the n nodes starting at head by insertion, given that the first
+ * start of them are already sorted, and returns the new head.  Like
+ * binarySort, but a list has no random access to search in.
+ */
+func (ls *listSorter) insertionSort(head *list.Element, start, n int) *list.Element {
+	last := head
+	for i := 1; i < start; i++ {
+		last = last.Next()
+	}
+
+	for ; start < n; start++ {
+		pivot := last.Next()
+		if !ls.lt(pivot.Value, last.Value) {
+			last = pivot
+			continue
+		}
+
+		// Find the leftmost node pivot can follow without breaking
+		// stability, i.e. the node after the last one <= pivot
+		at := last
+		for at != head && ls.lt(pivot.Value, at.Prev().Value) {
+			at = at.Prev()
+		}
+		ls.l.MoveBefore(pivot, at)
+		if at == head {
+			head = pivot
+		}
+	}
+	return head
+}
+
+func (ls *listSorter) pushRun(head *list.Element, runLen int) {
+	ls.runHead = append(ls.runHead, head)
+	ls.runLen = append(ls.runLen, runLen)
+}
+
+// mergeCollapse is mergeCollapse of timSortHandler for the run stack of
+// a list
+func (ls *listSorter) mergeCollapse() {
+	for len(ls.runLen) > 1 {
+		n := len(ls.runLen) - 2
+		if (n > 0 && ls.runLen[n-1] <= ls.runLen[n]+ls.runLen[n+1]) ||
+			(n > 1 && ls.runLen[n-2] <= ls.runLen[n-1]+ls.runLen[n]) {
+			if ls.runLen[n-1] < ls.runLen[n+1] {
+				n--
+			}
+			ls.mergeAt(n)
+		} else if ls.runLen[n] <= ls.runLen[n+1] {
+			ls.mergeAt(n)
+		} else {
+			break // Invariant is established
+		}
+	}
+}
+
+func (ls *listSorter) mergeForceCollapse() {
+	for len(ls.runLen) > 1 {
+		n := len(ls.runLen) - 2
+		if n > 0 && ls.runLen[n-1] < ls.runLen[n+1] {
+			n--
+		}
+		ls.mergeAt(n)
+	}
+}
+
+/**
+ * Merges the two runs at stack indices i and i+1 by moving each node of
+ * the second run in front of the first node of the first run greater than
+ * it.  Nothing moves if the runs are already in order.
+ */
+func (ls *listSorter) mergeAt(i int) {
+	head := ls.runHead[i]
+	a, lenA := head, ls.runLen[i]
+	b, lenB := ls.runHead[i+1], ls.runLen[i+1]
+
+	ls.runLen[i] = lenA + lenB
+	ls.runHead = append(ls.runHead[:i+1], ls.runHead[i+2:]...)
+	ls.runLen = append(ls.runLen[:i+1], ls.runLen[i+2:]...)
+
+	if !ls.lt(b.Value, b.Prev().Value) {
+		return // The last node of run A is <= the first of run B
+	}
+
+	for lenA > 0 && lenB > 0 {
+		if ls.lt(b.Value, a.Value) {
+			next := b.Next()
+			ls.l.MoveBefore(b, a)
+			if a == head {
+				head = b
+			}
+			b = next
+			lenB--
+		} else {
+			a = a.Next()
+			lenA--
+		}
+	}
+	ls.runHead[i] = head
+}
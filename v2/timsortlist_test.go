@@ -0,0 +1,93 @@
+package timsort
+
+import (
+	"container/list"
+	"slices"
+	"testing"
+)
+
+func listOf(a []val) *list.List {
+	l := list.New()
+	for _, v := range a {
+		l.PushBack(v)
+	}
+	return l
+}
+
+func listVals(l *list.List) []val {
+	a := make([]val, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		a = append(a, e.Value.(val))
+	}
+	return a
+}
+
+func TestSortList(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 32, 100, 1000, 10000} {
+		a := makeRandomVals(size)
+		want := slices.Clone(a)
+		Slice(want, valKeyLessThan)
+
+		l := listOf(a)
+		SortList(l, KeyLessThan)
+		if got := listVals(l); !slices.Equal(got, want) {
+			t.Errorf("size %d: not sorted stably", size)
+		}
+		if l.Len() != size {
+			t.Errorf("size %d: got length %d", size, l.Len())
+		}
+		if size > 0 && l.Back().Value != want[size-1] {
+			t.Errorf("size %d: back is %v", size, l.Back().Value)
+		}
+	}
+}
+
+func TestSortListKeepsElements(t *testing.T) {
+	l := listOf(makeRandomVals(1000))
+	nodes := map[*list.Element]bool{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		nodes[e] = true
+	}
+
+	SortList(l, KeyLessThan)
+	for e := l.Front(); e != nil; e = e.Next() {
+		if !nodes[e] {
+			t.Fatal("node was replaced")
+		}
+	}
+}
+
+func TestSortListPresorted(t *testing.T) {
+	// Four runs take two levels of linear merges
+	limits := map[string]int{"ascending": 1, "descending": 1, "runs": 3}
+	for _, name := range []string{"ascending", "descending", "runs"} {
+		a := make([]val, 10000)
+		for i := range a {
+			switch name {
+			case "ascending":
+				a[i] = val{i, i}
+			case "descending":
+				a[i] = val{len(a) - i, i}
+			case "runs":
+				a[i] = val{i % 2500, i}
+			}
+		}
+		want := slices.Clone(a)
+		Slice(want, valKeyLessThan)
+
+		count := 0
+		lt := func(a, b interface{}) bool {
+			count++
+			return KeyLessThan(a, b)
+		}
+		l := listOf(a)
+		SortList(l, lt)
+
+		if got := listVals(l); !slices.Equal(got, want) {
+			t.Errorf("%s: not sorted", name)
+		}
+		if count > limits[name]*len(a) {
+			t.Errorf("%s: %d comparisons for %d elements", name, count, len(a))
+		}
+	}
+}
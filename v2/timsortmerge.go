@@ -0,0 +1,93 @@
+package timsort
+
+// Merge appends the stable merge of the sorted slices a and b to dst and
+// returns the extended slice.  Elements of a come before equal elements of
+// b.  The merge gallops through long stretches won by either side, so
+// merging slices that barely interleave takes far fewer than len(a)+len(b)
+// comparisons.  dst must not overlap a or b.
+func Merge[T any](dst, a, b []T, lt LessFunc[T]) []T {
+	n := len(dst)
+	dst = append(dst, a...)
+	dst = append(dst, b...)
+	MergeAdjacent(dst[n:], len(a), lt)
+	return dst
+}
+
+// MergeAdjacent merges the sorted halves x[:mid] and x[mid:] in place, in a
+// stable fashion.  Temporary storage of min(mid, len(x)-mid) elements is
+// used.
+func MergeAdjacent[T any](x []T, mid int, lt LessFunc[T]) {
+	if mid <= 0 || mid >= len(x) {
+		return
+	}
+
+	ts := newTimSortG(x, lt)
+	ts.mergeRuns(0, mid, mid, len(x)-mid)
+}
+
+// MergeRuns sorts a slice that is made of consecutive sorted runs at known
+// positions, such as the concatenation of several sorted shards.  Each
+// boundary is the index at which a new run starts; boundaries must be
+// non-decreasing and lie within [0, len(a)].  Unlike Slice, which has to
+// rediscover the runs and extends short ones by insertion sort, MergeRuns
+// pushes the given runs as they are onto the pending-run stack and merges
+// them using the same collapse policy, so the result is stable with respect
+// to the original positions.
+func MergeRuns[T any](a []T, boundaries []int, lt LessFunc[T]) {
+	if len(a) < 2 || len(boundaries) == 0 {
+		return
+	}
+
+	ts := newTimSortG(a, lt)
+
+	// Runs need not be minRun long here, so the stack may have to be
+	// deeper, but never deeper than the number of runs or than the stack
+	// of Tim's C version.
+	if stackLen := len(boundaries) + 1; stackLen > len(ts.runBase) {
+		if stackLen > 85 {
+			stackLen = 85
+		}
+		ts.runBase = make([]int, stackLen)
+		ts.runLen = make([]int, stackLen)
+	}
+
+	lo := 0
+	for i := 0; i <= len(boundaries); i++ {
+		hi := len(a)
+		if i < len(boundaries) {
+			hi = boundaries[i]
+		}
+		if hi <= lo {
+			continue // Skip empty runs
+		}
+
+		ts.pushRun(lo, hi-lo)
+		ts.mergeCollapse()
+		lo = hi
+	}
+
+	ts.mergeForceCollapse()
+}
+
+// InsertSorted appends the elements of batch to the sorted slice a, keeping
+// it sorted, and returns the extended slice.  Elements of batch come after
+// equal elements of a and keep their relative order.  batch is not
+// modified.
+//
+// Only the appended copy of batch is sorted, and it is then merged into a
+// by galloping, so adding k elements to n takes about O(k log k + k log n)
+// comparisons rather than the O(n) of re-sorting everything.
+func InsertSorted[T any](a, batch []T, lt LessFunc[T]) []T {
+	n := len(a)
+	a = append(a, batch...)
+	SortAppended(a, n, lt)
+	return a
+}
+
+// SortAppended sorts a, of which a[:sortedLen] is already sorted, such as
+// after appending new elements to a sorted slice in place.  It sorts only
+// a[sortedLen:] and merges it into the sorted prefix, see InsertSorted.
+func SortAppended[T any](a []T, sortedLen int, lt LessFunc[T]) {
+	Slice(a[sortedLen:], lt)
+	MergeAdjacent(a, sortedLen, lt)
+}
@@ -0,0 +1,152 @@
+package timsort
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func makeSortedVals(size, keys, order int) []val {
+	a := make([]val, size)
+
+	for i := 0; i < size; i++ {
+		a[i] = val{rand.Intn(keys), order + i}
+	}
+	Slice(a, valKeyLessThan)
+
+	return a
+}
+
+func TestMerge(t *testing.T) {
+	sizes := []int{0, 1, 2, 7, 100, 1000}
+	for _, n := range sizes {
+		for _, m := range sizes {
+			for _, keys := range []int{1, 10, 10000} {
+				a := makeSortedVals(n, keys, 0)
+				b := makeSortedVals(m, keys, n)
+
+				prefix := []val{{-1, -1}}
+				merged := Merge(prefix, a, b, valKeyLessThan)
+				if len(merged) != 1+n+m || merged[0] != prefix[0] {
+					t.Fatalf("n=%d m=%d: bad length or prefix", n, m)
+				}
+				if !isSortedVals(merged[1:], valKeyOrderLessThan) {
+					t.Fatalf("n=%d m=%d keys=%d: not merged", n, m, keys)
+				}
+			}
+		}
+	}
+}
+
+func TestMergeAdjacent(t *testing.T) {
+	for _, mid := range []int{0, 1, 10, 500, 999, 1000} {
+		a := makeSortedVals(mid, 50, 0)
+		b := makeSortedVals(1000-mid, 50, mid)
+		x := append(a, b...)
+
+		MergeAdjacent(x, mid, valKeyLessThan)
+		if !isSortedVals(x, valKeyOrderLessThan) {
+			t.Fatalf("mid=%d: not merged", mid)
+		}
+	}
+}
+
+func TestMergeGallops(t *testing.T) {
+	// b falls into a handful of gaps in a, galloping should skip the rest
+	size := 100 * 1024
+	a := make([]int, size)
+	for i := range a {
+		a[i] = 2 * i
+	}
+	b := []int{-1, 1001, 1003, 50001, 2 * size}
+
+	compares := 0
+	merged := Merge(nil, a, b, func(x, y int) bool {
+		compares++
+		return x < y
+	})
+	if !sort.IntsAreSorted(merged) || len(merged) != size+len(b) {
+		t.Fatal("not merged")
+	}
+	if compares > 1000 {
+		t.Errorf("%d compares for merging %d into %d elements", compares, len(b), size)
+	}
+}
+
+func TestMergeRuns(t *testing.T) {
+	for _, runs := range []int{1, 2, 3, 10, 100, 1000} {
+		var a []val
+		var boundaries []int
+		for i := 0; i < runs; i++ {
+			boundaries = append(boundaries, len(a))
+			a = append(a, makeSortedVals(rand.Intn(50), 20, len(a))...)
+		}
+		boundaries = append(boundaries, len(a))
+
+		MergeRuns(a, boundaries, valKeyLessThan)
+		if !isSortedVals(a, valKeyOrderLessThan) {
+			t.Fatalf("runs=%d: not sorted", runs)
+		}
+	}
+}
+
+func TestMergeRunsShortRuns(t *testing.T) {
+	// every element is its own run, which needs the deepest stack
+	size := 10000
+	a := makeRandomVals(size)
+	boundaries := make([]int, size)
+	for i := range boundaries {
+		boundaries[i] = i
+	}
+
+	MergeRuns(a, boundaries, valKeyLessThan)
+	if !isSortedVals(a, valKeyOrderLessThan) {
+		t.Fatal("not sorted")
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		for _, k := range []int{0, 1, 10, 1000} {
+			a := makeSortedVals(n, 50, 0)
+			batch := makeRandomVals(k)
+			for i := range batch {
+				batch[i].order += n
+			}
+			orig := append([]val(nil), batch...)
+
+			a = InsertSorted(a, batch, valKeyLessThan)
+			if len(a) != n+k || !isSortedVals(a, valKeyOrderLessThan) {
+				t.Fatalf("n=%d k=%d: not sorted", n, k)
+			}
+			for i := range batch {
+				if batch[i] != orig[i] {
+					t.Fatalf("n=%d k=%d: batch modified", n, k)
+				}
+			}
+		}
+	}
+}
+
+func TestSortAppendedCompares(t *testing.T) {
+	size := 1024 * 1024
+	a := make([]int, size, size+1000)
+	for i := range a {
+		a[i] = 1000 * i
+	}
+	for i := 0; i < 1000; i++ {
+		a = append(a, rand.Intn(1000*size))
+	}
+
+	compares := 0
+	SortAppended(a, size, func(x, y int) bool {
+		compares++
+		return x < y
+	})
+	if !sort.IntsAreSorted(a) {
+		t.Fatal("not sorted")
+	}
+	if compares > 100*1000 {
+		t.Errorf("%d compares for appending 1000 to %d elements", compares, size)
+	}
+}
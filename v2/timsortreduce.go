@@ -0,0 +1,222 @@
+package timsort
+
+// SortReduce sorts a and folds every group of equal elements into a single
+// element.  Elements are folded by calling combine(dst, src), where dst
+// points at an element that came before src in the original order.  As
+// either of them may already be the result of earlier calls, combine must
+// be associative.  SortReduce returns the number of elements left, which
+// are sorted in a[:n]; the rest of a is zeroed.
+//
+// Equal elements are combined as soon as they meet, while runs are formed
+// and while they are merged, so runs shrink as the sort goes and fully
+// duplicated runs are never merged.  This is useful for word counts and
+// similar aggregations:
+//
+//	n := timsort.SortReduce(counts, byWord, func(dst *count, src count) {
+//		dst.n += src.n
+//	})
+//	counts = counts[:n]
+func SortReduce[T any](a []T, lt LessFunc[T], combine func(dst *T, src T)) int {
+	n := len(a)
+	if n < 2 {
+		return n
+	}
+
+	ts := newTimSortG(a, lt)
+	ts.combine = combine
+	minRun := minRunLength(n)
+
+	// Reduced runs may be shorter than minRun, so the stack may have to be
+	// deeper, but never deeper than the number of runs.
+	if stackLen := n/minRun + 1; stackLen > len(ts.runBase) {
+		if stackLen > 85 {
+			stackLen = 85
+		}
+		ts.runBase = make([]int, stackLen)
+		ts.runLen = make([]int, stackLen)
+	}
+
+	/**
+	 * March over the array like Slice does, but reduce every run before
+	 * pushing it and move it down to the end of the previous runs, which
+	 * may have shrunk.
+	 */
+	lo := 0
+	top := 0 // End of the runs on the stack
+	for lo < n {
+		runLen := countRunAndMakeAscendingG(a, lo, n, lt)
+
+		// If run is short, extend to min(minRun, nRemaining)
+		if runLen < minRun {
+			force := minRun
+			if n-lo <= minRun {
+				force = n - lo
+			}
+			binarySortG(a, lo, lo+force, lo+runLen, lt)
+			runLen = force
+		}
+
+		reduced := reduceRun(a, lo, lo+runLen, top, combine, lt)
+		ts.pushRun(top, reduced)
+		ts.mergeCollapse()
+
+		lo += runLen
+		top = ts.runBase[ts.stackSize-1] + ts.runLen[ts.stackSize-1]
+	}
+
+	ts.mergeForceCollapse()
+
+	clear(a[ts.runLen[0]:])
+	return ts.runLen[0]
+}
+
+/**
+ * Combines the equal elements of the sorted range a[lo:hi] and writes the
+ * result to a[dest:], which may overlap the range as long as dest <= lo.
+ *
+ * @return the length of the reduced run
+ */
+func reduceRun[T any](a []T, lo, hi, dest int, combine func(dst *T, src T), lt LessFunc[T]) int {
+	base := dest
+	for i := lo; i < hi; i++ {
+		if dest > base && !lt(a[dest-1], a[i]) {
+			combine(&a[dest-1], a[i])
+		} else {
+			a[dest] = a[i]
+			dest++
+		}
+	}
+	return dest - base
+}
+
+/**
+ * Like mergeAt, but for SortReduce: the merged run may be shorter than the
+ * two runs were, in which case a run that follows them on the stack is
+ * moved down to close the gap.
+ *
+ * @param i stack index of the first of the two runs to merge
+ */
+func (h *timSortHandlerG[T]) mergeAtReduce(i int) {
+	base1 := h.runBase[i]
+	len1 := h.runLen[i]
+	base2 := h.runBase[i+1]
+	len2 := h.runLen[i+1]
+
+	merged := h.mergeReduce(base1, len1, base2, len2)
+	h.runLen[i] = merged
+	if i == h.stackSize-3 {
+		base3 := h.runBase[i+2]
+		len3 := h.runLen[i+2]
+		copy(h.a[base1+merged:], h.a[base3:base3+len3])
+		h.runBase[i+1] = base1 + merged
+		h.runLen[i+1] = len3
+	}
+	h.stackSize--
+}
+
+/**
+ * Merges two adjacent reduced runs, in a stable fashion, combining the
+ * elements that compare equal.  Within a reduced run no two elements are
+ * equal, so every element meets at most one equal element in the other
+ * run.  The merged run starts at base1.
+ *
+ * Like mergeLo, this does the straightforward thing until one run starts
+ * winning consistently and then gallops, adjusting minGallop as it goes.
+ *
+ * @param base1 index of first element in first run to be merged
+ * @param len1  length of first run to be merged (must be > 0)
+ * @param base2 index of first element in second run to be merged
+ *        (must be base1 + len1)
+ * @param len2  length of second run to be merged (must be > 0)
+ * @return the length of the merged run
+ */
+func (h *timSortHandlerG[T]) mergeReduce(base1, len1, base2, len2 int) int {
+	a := h.a // For performance
+	lt := h.lt
+
+	/*
+	 * Elements of run1 less than the first element of run2 are already
+	 * in place.  Unlike in mergeAt, equal ones are not, they have to be
+	 * combined.
+	 */
+	k := gallopLeftG(a[base2], a, base1, len1, 0, lt)
+	dest := base1 + k
+	len1 -= k
+	if len1 == 0 {
+		return k + len2
+	}
+
+	// Copy the rest of the first run into temp array
+	tmp := h.ensureCapacity(len1)
+	if len(tmp) < len1 { // Run 1 may be longer than half of the array
+		h.tmp = make([]T, len1)
+		tmp = h.tmp
+	}
+	copy(tmp, a[dest:dest+len1])
+
+	cursor1 := 0         // Indexes into tmp array
+	cursor2 := base2     // Indexes into a
+	end2 := base2 + len2 // End of run2 in a
+	minGallop := h.minGallop
+	count1 := 0 // Number of times in a row that first run won
+	count2 := 0 // Number of times in a row that second run won
+
+	for cursor1 < len1 && cursor2 < end2 {
+		if count1 < minGallop && count2 < minGallop {
+			switch {
+			case lt(a[cursor2], tmp[cursor1]):
+				a[dest] = a[cursor2]
+				cursor2++
+				count2++
+				count1 = 0
+			case lt(tmp[cursor1], a[cursor2]):
+				a[dest] = tmp[cursor1]
+				cursor1++
+				count1++
+				count2 = 0
+			default:
+				h.combine(&tmp[cursor1], a[cursor2])
+				a[dest] = tmp[cursor1]
+				cursor1++
+				cursor2++
+				count1 = 0
+				count2 = 0
+			}
+			dest++
+			continue
+		}
+
+		/*
+		 * One run is winning so consistently that galloping may be a
+		 * huge win.  Copy the stretches of either run that are less than
+		 * the head of the other one; heads that are equal are left for
+		 * the straightforward loop to combine.
+		 */
+		count1 = gallopLeftG(a[cursor2], tmp, cursor1, len1-cursor1, 0, lt)
+		copy(a[dest:], tmp[cursor1:cursor1+count1])
+		dest += count1
+		cursor1 += count1
+		if cursor1 == len1 {
+			break
+		}
+
+		count2 = gallopLeftG(tmp[cursor1], a, cursor2, end2-cursor2, 0, lt)
+		copy(a[dest:], a[cursor2:cursor2+count2])
+		dest += count2
+		cursor2 += count2
+
+		if count1 < minGallop && count2 < minGallop {
+			minGallop += 2 // Penalize for leaving gallop mode
+			count1 = 0
+			count2 = 0
+		} else if minGallop > 1 {
+			minGallop--
+		}
+	}
+	h.minGallop = minGallop // Write back to field
+
+	// At most one of the runs has elements left
+	dest += copy(a[dest:], tmp[cursor1:len1])
+	dest += copy(a[dest:], a[cursor2:end2])
+	return dest - base1
+}
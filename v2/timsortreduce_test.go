@@ -0,0 +1,78 @@
+package timsort
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+type wordCount struct {
+	word  int
+	count int
+	seen  string // orders that were combined, in combine order
+}
+
+func wordLessThan(a, b wordCount) bool {
+	return a.word < b.word
+}
+
+func TestSortReduce(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 32, 100, 1024, 10 * 1024} {
+		for _, words := range []int{1, 10, 1000, 1 << 30} {
+			a := make([]wordCount, size)
+			want := make(map[int]string)
+			for i := range a {
+				w := rand.Intn(words)
+				a[i] = wordCount{w, 1, fmt.Sprint(i, ",")}
+				want[w] += a[i].seen
+			}
+
+			n := SortReduce(a, wordLessThan, func(dst *wordCount, src wordCount) {
+				dst.count += src.count
+				dst.seen += src.seen
+			})
+
+			if n != len(want) {
+				t.Fatalf("size=%d words=%d: got %d elements, want %d", size, words, n, len(want))
+			}
+			total := 0
+			for i, wc := range a[:n] {
+				if i > 0 && !wordLessThan(a[i-1], wc) {
+					t.Fatalf("size=%d words=%d: not sorted at %d", size, words, i)
+				}
+				if wc.seen != want[wc.word] {
+					t.Fatalf("size=%d words=%d: word %d combined as %q, want %q", size, words, wc.word, wc.seen, want[wc.word])
+				}
+				total += wc.count
+			}
+			if total != size {
+				t.Fatalf("size=%d words=%d: total count %d", size, words, total)
+			}
+			for _, wc := range a[n:] {
+				if wc != (wordCount{}) {
+					t.Fatalf("size=%d words=%d: tail not cleared", size, words)
+				}
+			}
+		}
+	}
+}
+
+func TestSortReducePresorted(t *testing.T) {
+	// sorted shards with overlapping keys exercise the galloping merge
+	var a []int
+	for shard := 0; shard < 20; shard++ {
+		for k := 0; k < 5000; k++ {
+			a = append(a, shard*1000+k)
+		}
+	}
+
+	n := SortReduce(a, intLessThan, func(dst *int, src int) {})
+	if n != 24000 {
+		t.Fatalf("got %d elements, want 24000", n)
+	}
+	for i, v := range a[:n] {
+		if v != i {
+			t.Fatalf("index %d: got %d", i, v)
+		}
+	}
+}
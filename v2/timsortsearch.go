@@ -0,0 +1,48 @@
+package timsort
+
+import "cmp"
+
+// GallopLeft returns the position at which key would be inserted into the
+// sorted slice x before any equal elements, that is the smallest index i
+// such that !lt(x[i], key), or len(x).
+//
+// The search starts at hint and gallops away from it in exponentially
+// growing steps before narrowing down with a binary search, so it takes
+// O(log d) comparisons where d is the distance between hint and the
+// result.  This makes it a good fit for cursor-style lookups of keys that
+// are close to each other: pass the previous result as the next hint.
+// A hint outside of x is clamped.
+func GallopLeft[T any](x []T, key T, hint int, lt LessFunc[T]) int {
+	if len(x) == 0 {
+		return 0
+	}
+	return gallopLeftG(key, x, 0, len(x), clampHint(hint, len(x)), lt)
+}
+
+// GallopRight is like GallopLeft, except that it returns the position after
+// any elements equal to key, that is the smallest index i such that
+// lt(key, x[i]), or len(x).
+func GallopRight[T any](x []T, key T, hint int, lt LessFunc[T]) int {
+	if len(x) == 0 {
+		return 0
+	}
+	return gallopRightG(key, x, 0, len(x), clampHint(hint, len(x)), lt)
+}
+
+// SearchFrom searches for key in the sorted slice x like slices.BinarySearch,
+// but gallops from hint like GallopLeft.  It returns the position where key
+// is found, or would be inserted, and whether it is present.
+func SearchFrom[T cmp.Ordered](x []T, key T, hint int) (int, bool) {
+	i := GallopLeft(x, key, hint, cmp.Less[T])
+	return i, i < len(x) && cmp.Compare(x[i], key) == 0
+}
+
+func clampHint(hint, n int) int {
+	if hint < 0 {
+		return 0
+	}
+	if hint >= n {
+		return n - 1
+	}
+	return hint
+}
@@ -0,0 +1,56 @@
+package timsort
+
+import (
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestGallop(t *testing.T) {
+	for _, x := range [][]int{{}, {5}, {1, 2, 2, 2, 3}, makeSortedInts(1000, 100)} {
+		for key := -1; key <= 101; key++ {
+			left := sort.SearchInts(x, key)
+			right := sort.SearchInts(x, key+1)
+			for _, hint := range []int{-3, 0, 1, len(x) / 2, len(x) - 1, len(x) + 7} {
+				if got := GallopLeft(x, key, hint, intLessThan); got != left {
+					t.Fatalf("GallopLeft(%d, hint=%d): got %d, want %d", key, hint, got, left)
+				}
+				if got := GallopRight(x, key, hint, intLessThan); got != right {
+					t.Fatalf("GallopRight(%d, hint=%d): got %d, want %d", key, hint, got, right)
+				}
+			}
+		}
+	}
+}
+
+func TestSearchFromCursor(t *testing.T) {
+	size := 1024 * 1024
+	x := make([]int, size)
+	for i := range x {
+		x[i] = 2 * i
+	}
+
+	// successive keys close together: each lookup starts at the last one
+	compares := 0
+	lt := func(a, b int) bool {
+		compares++
+		return a < b
+	}
+	hint := 0
+	for key := 1000; key < 2000; key++ {
+		hint = GallopLeft(x, key, hint, lt)
+		if want, _ := slices.BinarySearch(x, key); hint != want {
+			t.Fatalf("key=%d: got %d, want %d", key, hint, want)
+		}
+	}
+	if compares > 5000 {
+		t.Errorf("%d compares for 1000 lookups", compares)
+	}
+
+	if i, ok := SearchFrom(x, 4000, 0); i != 2000 || !ok {
+		t.Errorf("SearchFrom(4000): got %d, %v", i, ok)
+	}
+	if i, ok := SearchFrom(x, 4001, size-1); i != 2001 || ok {
+		t.Errorf("SearchFrom(4001): got %d, %v", i, ok)
+	}
+}
@@ -0,0 +1,137 @@
+package timsort
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// NthElement rearranges a so that a[k] is the element that would be there
+// if a were sorted, all elements before it are not greater and all elements
+// after it are not less.  Unlike sorting, this takes O(n) comparisons on
+// average.  NthElement is not stable: of several elements equal to the
+// k-th, any one may end up at a[k].  See NthElementStable.  NthElement
+// panics if k is not an index of a.
+func NthElement[T any](a []T, k int, lt LessFunc[T]) {
+	checkIndex("NthElement", k, len(a))
+	selectG(a, 0, len(a), k, lt)
+}
+
+// NthElementStable returns the element that Slice would put at index k,
+// taking the original position of equal elements into account, without
+// modifying a.  It takes O(n) comparisons on average and O(n) extra space.
+// NthElementStable panics if k is not an index of a.
+func NthElementStable[T any](a []T, k int, lt LessFunc[T]) T {
+	checkIndex("NthElementStable", k, len(a))
+
+	idx := make([]int, len(a))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	// Order by element, then by original position
+	selectG(idx, 0, len(idx), k, func(i, j int) bool {
+		return lt(a[i], a[j]) || (i < j && !lt(a[j], a[i]))
+	})
+	return a[idx[k]]
+}
+
+// Quantiles returns the order statistics of a for each of the quantiles qs,
+// which are clamped to [0, 1].  The q-quantile is the element that a sorted
+// a would hold at index q*(len(a)-1), rounded to the nearest integer.
+// Quantiles rearranges a as NthElement would for every requested index, in
+// O(n log len(qs)) comparisons on average.  It returns nil if a is empty,
+// and panics if any of qs is NaN.
+func Quantiles[T any](a []T, qs []float64, lt LessFunc[T]) []T {
+	if len(a) == 0 {
+		return nil
+	}
+
+	ks := make([]int, len(qs))
+	for i, q := range qs {
+		if math.IsNaN(q) {
+			panic("timsort: Quantiles: quantile is NaN")
+		}
+		q = math.Max(0, math.Min(1, q))
+		ks[i] = int(math.Round(q * float64(len(a)-1)))
+	}
+	distinct := slices.Compact(slices.Sorted(slices.Values(ks)))
+	multiSelect(a, 0, len(a), distinct, lt)
+
+	res := make([]T, len(ks))
+	for i, k := range ks {
+		res[i] = a[k]
+	}
+	return res
+}
+
+// checkIndex panics if k is not an index of a slice of length n
+func checkIndex(fn string, k, n int) {
+	if k < 0 || k >= n {
+		panic(fmt.Sprintf("timsort: %s: index %d out of range [0:%d]", fn, k, n))
+	}
+}
+
+/**
+ * Places the order statistics for all of the sorted indexes ks, which lie
+ * in [lo, hi), by selecting the middle one and recursing into both sides.
+ */
+func multiSelect[T any](a []T, lo, hi int, ks []int, lt LessFunc[T]) {
+	if len(ks) == 0 {
+		return
+	}
+	m := len(ks) / 2
+	selectG(a, lo, hi, ks[m], lt)
+	multiSelect(a, lo, ks[m], ks[:m], lt)
+	multiSelect(a, ks[m]+1, hi, ks[m+1:], lt)
+}
+
+/**
+ * Quickselect on a[lo:hi]: partitions around a pivot into elements less
+ * than, equal to and greater than it, and continues with the part that
+ * holds index k.  Grouping equal elements keeps inputs with many
+ * duplicates linear.  Small ranges are finished with binarySort.
+ */
+func selectG[T any](a []T, lo, hi, k int, lt LessFunc[T]) {
+	for hi-lo > minMerge {
+		// Median of three random elements
+		x, y, z := a[lo+rand.IntN(hi-lo)], a[lo+rand.IntN(hi-lo)], a[lo+rand.IntN(hi-lo)]
+		if lt(y, x) {
+			x, y = y, x
+		}
+		if lt(z, y) {
+			y = z
+			if lt(y, x) {
+				y = x
+			}
+		}
+		pivot := y
+
+		// Invariant: a[lo:l] < pivot, a[l:i] == pivot, a[g:hi] > pivot
+		l, i, g := lo, lo, hi
+		for i < g {
+			switch {
+			case lt(a[i], pivot):
+				a[l], a[i] = a[i], a[l]
+				l++
+				i++
+			case lt(pivot, a[i]):
+				g--
+				a[i], a[g] = a[g], a[i]
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case k < l:
+			hi = l
+		case k >= g:
+			lo = g
+		default:
+			return // a[k] is equal to the pivot
+		}
+	}
+	binarySortG(a, lo, hi, lo, lt)
+}
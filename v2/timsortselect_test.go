@@ -0,0 +1,98 @@
+package timsort
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNthElement(t *testing.T) {
+	for _, size := range []int{1, 2, 31, 100, 1000, 100 * 1024} {
+		a := makeRandomArrayI(size)
+		sorted := slices.Clone(a)
+		slices.Sort(sorted)
+
+		for _, k := range []int{0, size / 3, size / 2, size - 1} {
+			b := slices.Clone(a)
+			NthElement(b, k, intLessThan)
+			if b[k] != sorted[k] {
+				t.Fatalf("size=%d k=%d: got %d, want %d", size, k, b[k], sorted[k])
+			}
+			for i := range b {
+				if i < k && b[i] > b[k] || i > k && b[i] < b[k] {
+					t.Fatalf("size=%d k=%d: not partitioned at %d", size, k, i)
+				}
+			}
+		}
+	}
+}
+
+func TestNthElementStable(t *testing.T) {
+	a := makeRandomVals(10000)
+	sorted := slices.Clone(a)
+	Slice(sorted, valKeyLessThan)
+
+	for _, k := range []int{0, 1, 500, 5000, 9999} {
+		if got := NthElementStable(a, k, valKeyLessThan); got != sorted[k] {
+			t.Fatalf("k=%d: got %v, want %v", k, got, sorted[k])
+		}
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	size := 100 * 1024
+	a := makeRandomArrayI(size)
+	sorted := slices.Clone(a)
+	slices.Sort(sorted)
+
+	qs := []float64{0.99, 0.5, -1, 0.25, 0.5, 2, 0.999}
+	got := Quantiles(a, qs, intLessThan)
+	for i, q := range qs {
+		k := int(float64(size-1)*min(max(q, 0), 1) + 0.5)
+		if got[i] != sorted[k] {
+			t.Errorf("q=%v: got %d, want %d", q, got[i], sorted[k])
+		}
+	}
+
+	if Quantiles([]int{}, qs, intLessThan) != nil {
+		t.Error("quantiles of nothing")
+	}
+}
+
+func TestNthElementCompares(t *testing.T) {
+	size := 1024 * 1024
+	a := makeRandomArrayI(size)
+	for i := range a {
+		a[i] = a[i]*size + i // distinct keys
+	}
+
+	compares := 0
+	NthElement(a, size/2, func(x, y int) bool {
+		compares++
+		return x < y
+	})
+	if compares > 10*size {
+		t.Errorf("%d compares for median of %d", compares, size)
+	}
+}
+
+func TestSelectPanics(t *testing.T) {
+	a := []int{3, 1, 2}
+	for name, f := range map[string]func(){
+		"NthElement -1":       func() { NthElement(a, -1, intLessThan) },
+		"NthElement len":      func() { NthElement(a, len(a), intLessThan) },
+		"NthElement empty":    func() { NthElement([]int{}, 0, intLessThan) },
+		"NthElementStable -1": func() { NthElementStable(a, -1, intLessThan) },
+		"NthElementStable 5":  func() { NthElementStable(a, 5, intLessThan) },
+		"Quantiles NaN":       func() { Quantiles(a, []float64{0.5, math.NaN()}, intLessThan) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,128 @@
+package timsort
+
+// SetSemantics selects how the set operations treat equal elements.
+type SetSemantics int
+
+const (
+	// Distinct treats each input as a set: a run of equal elements counts
+	// as one element and the output holds no duplicates.
+	Distinct SetSemantics = iota
+
+	// Multiset counts duplicates: an element that occurs m times in a and
+	// n times in b occurs max(m, n) times in Union, min(m, n) times in
+	// Intersect, m-n times in Difference and |m-n| times in
+	// SymmetricDifference.
+	Multiset
+)
+
+const (
+	opUnion = iota
+	opIntersect
+	opDifference
+	opSymmetricDifference
+)
+
+// Union appends the union of the sorted slices a and b to dst and returns
+// the extended slice.  Of equal elements, those from a are preferred and
+// come first.
+//
+// Like the other set operations, Union gallops over stretches of one input
+// that have no counterpart in the other, so inputs of very different sizes
+// are combined in far fewer comparisons than a plain merge takes.
+func Union[T any](dst, a, b []T, lt LessFunc[T], s SetSemantics) []T {
+	return setOp(dst, a, b, lt, s, opUnion)
+}
+
+// Intersect appends the elements of the sorted slice a that also occur in
+// the sorted slice b to dst and returns the extended slice.  Intersecting m
+// elements with n >= m elements takes O(m log(n/m)) comparisons.
+func Intersect[T any](dst, a, b []T, lt LessFunc[T], s SetSemantics) []T {
+	return setOp(dst, a, b, lt, s, opIntersect)
+}
+
+// Difference appends the elements of the sorted slice a that do not occur in
+// the sorted slice b to dst and returns the extended slice.  With Multiset
+// semantics the trailing elements of each run of equal elements are kept.
+func Difference[T any](dst, a, b []T, lt LessFunc[T], s SetSemantics) []T {
+	return setOp(dst, a, b, lt, s, opDifference)
+}
+
+// SymmetricDifference appends the elements that occur in exactly one of the
+// sorted slices a and b to dst, in sorted order, and returns the extended
+// slice.
+func SymmetricDifference[T any](dst, a, b []T, lt LessFunc[T], s SetSemantics) []T {
+	return setOp(dst, a, b, lt, s, opSymmetricDifference)
+}
+
+func setOp[T any](dst, a, b []T, lt LessFunc[T], s SetSemantics, op int) []T {
+	keepA := op != opIntersect                            // Keep elements only in a
+	keepB := op == opUnion || op == opSymmetricDifference // Keep elements only in b
+	distinct := s == Distinct
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		// Stretch of a below the head of b
+		if k := gallopLeftG(b[j], a, i, len(a)-i, 0, lt); k > 0 {
+			if keepA {
+				dst = appendRun(dst, a[i:i+k], lt, distinct)
+			}
+			i += k
+			if i == len(a) {
+				break
+			}
+		}
+
+		// Stretch of b below the head of a
+		if k := gallopLeftG(a[i], b, j, len(b)-j, 0, lt); k > 0 {
+			if keepB {
+				dst = appendRun(dst, b[j:j+k], lt, distinct)
+			}
+			j += k
+			continue
+		}
+
+		// a[i] and b[j] are equal, find both runs of equal elements
+		m := gallopRightG(a[i], a, i, len(a)-i, 0, lt)
+		n := gallopRightG(a[i], b, j, len(b)-j, 0, lt)
+		switch {
+		case distinct && (op == opUnion || op == opIntersect):
+			dst = append(dst, a[i])
+		case distinct:
+		case op == opUnion:
+			dst = append(dst, a[i:i+m]...)
+			if n > m {
+				dst = append(dst, b[j+m:j+n]...)
+			}
+		case op == opIntersect:
+			dst = append(dst, a[i:i+min(m, n)]...)
+		case m > n:
+			dst = append(dst, a[i+n:i+m]...)
+		case op == opSymmetricDifference:
+			dst = append(dst, b[j+m:j+n]...)
+		}
+		i += m
+		j += n
+	}
+
+	if keepA {
+		dst = appendRun(dst, a[i:], lt, distinct)
+	}
+	if keepB {
+		dst = appendRun(dst, b[j:], lt, distinct)
+	}
+	return dst
+}
+
+// appendRun appends the sorted run to dst, keeping only the first of
+// equal elements if distinct is set
+func appendRun[T any](dst, run []T, lt LessFunc[T], distinct bool) []T {
+	if !distinct {
+		return append(dst, run...)
+	}
+	for k := range run {
+		if k == 0 || lt(run[k-1], run[k]) {
+			dst = append(dst, run[k])
+		}
+	}
+	return dst
+}
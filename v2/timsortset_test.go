@@ -0,0 +1,134 @@
+package timsort
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func makeSortedInts(size, keys int) []int {
+	a := make([]int, size)
+	for i := range a {
+		a[i] = rand.Intn(keys)
+	}
+	slices.Sort(a)
+	return a
+}
+
+// setReference computes a set operation by counting occurrences
+func setReference(a, b []int, s SetSemantics, op int) []int {
+	count := func(x []int) map[int]int {
+		c := make(map[int]int)
+		for _, v := range x {
+			c[v]++
+			if s == Distinct {
+				c[v] = 1
+			}
+		}
+		return c
+	}
+	ca, cb := count(a), count(b)
+
+	var keys []int
+	for v := range ca {
+		keys = append(keys, v)
+	}
+	for v := range cb {
+		if ca[v] == 0 {
+			keys = append(keys, v)
+		}
+	}
+	slices.Sort(keys)
+
+	res := []int{}
+	for _, v := range keys {
+		m, n := ca[v], cb[v]
+		var c int
+		switch op {
+		case opUnion:
+			c = max(m, n)
+		case opIntersect:
+			c = min(m, n)
+		case opDifference:
+			c = max(m-n, 0)
+		case opSymmetricDifference:
+			c = max(m-n, n-m)
+		}
+		for ; c > 0; c-- {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func TestSetOperations(t *testing.T) {
+	ops := map[int]func(dst, a, b []int, lt LessFunc[int], s SetSemantics) []int{
+		opUnion:               Union[int],
+		opIntersect:           Intersect[int],
+		opDifference:          Difference[int],
+		opSymmetricDifference: SymmetricDifference[int],
+	}
+	sizes := []int{0, 1, 5, 100, 1000}
+	for op, f := range ops {
+		for _, s := range []SetSemantics{Distinct, Multiset} {
+			for _, n := range sizes {
+				for _, m := range sizes {
+					for _, keys := range []int{3, 50, 10000} {
+						a := makeSortedInts(n, keys)
+						b := makeSortedInts(m, keys)
+
+						got := f([]int{}, a, b, intLessThan, s)
+						want := setReference(a, b, s, op)
+						if !slices.Equal(got, want) {
+							t.Fatalf("op=%d s=%d n=%d m=%d keys=%d:\ngot  %v\nwant %v", op, s, n, m, keys, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSetOperationsStable(t *testing.T) {
+	a := []val{{1, 0}, {1, 1}, {2, 2}, {3, 3}}
+	b := []val{{1, 10}, {1, 11}, {1, 12}, {3, 13}, {4, 14}}
+
+	got := Union(nil, a, b, valKeyLessThan, Multiset)
+	want := []val{{1, 0}, {1, 1}, {1, 12}, {2, 2}, {3, 3}, {4, 14}}
+	if !slices.Equal(got, want) {
+		t.Errorf("union: got %v, want %v", got, want)
+	}
+
+	got = Intersect(nil, b, a, valKeyLessThan, Distinct)
+	want = []val{{1, 10}, {3, 13}}
+	if !slices.Equal(got, want) {
+		t.Errorf("intersect: got %v, want %v", got, want)
+	}
+
+	got = SymmetricDifference(nil, a, b, valKeyLessThan, Multiset)
+	want = []val{{1, 12}, {2, 2}, {4, 14}}
+	if !slices.Equal(got, want) {
+		t.Errorf("symmetric difference: got %v, want %v", got, want)
+	}
+}
+
+func TestIntersectSkewed(t *testing.T) {
+	size := 1024 * 1024
+	big := make([]int, size)
+	for i := range big {
+		big[i] = i
+	}
+	small := []int{3, 1000, 50000, 50001, 700000, size + 5}
+
+	compares := 0
+	got := Intersect(nil, small, big, func(a, b int) bool {
+		compares++
+		return a < b
+	}, Distinct)
+	if !slices.Equal(got, small[:5]) {
+		t.Fatalf("got %v", got)
+	}
+	if compares > 500 {
+		t.Errorf("%d compares for intersecting %d with %d elements", compares, len(small), size)
+	}
+}
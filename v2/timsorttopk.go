@@ -0,0 +1,111 @@
+package timsort
+
+// SortTopK rearranges a so that a[:k] holds the k first elements of a in
+// sorted order, exactly as Slice would have left them, including the order
+// of equal elements.  The order of the remaining elements is unspecified.
+//
+// SortTopK keeps the current top k sorted and collects the elements that
+// beat the k-th of them into a batch of up to k elements, which is sorted
+// and merged in when full.  Elements that cannot make it into the top k
+// cost one comparison, so for k much smaller than len(a) this takes close
+// to O(n) comparisons on most inputs and O(n log k) at worst.
+func SortTopK[T any](a []T, k int, lt LessFunc[T]) {
+	if k <= 0 {
+		return
+	}
+	if 2*k >= len(a) {
+		Slice(a, lt)
+		return
+	}
+
+	Slice(a[:k], lt)
+
+	// a[k:k+n] is the batch, a[k+n:i] are rejected elements
+	n := 0
+	for i := k; i < len(a); i++ {
+		if !lt(a[i], a[k-1]) {
+			continue // Equal elements that come later lose
+		}
+
+		a[k+n], a[i] = a[i], a[k+n]
+		n++
+		if n == k {
+			mergeTopK(a[:2*k], k, lt)
+			n = 0
+		}
+	}
+	mergeTopK(a[:k+n], k, lt)
+}
+
+/**
+ * Sorts the batch a[k:] and merges it into the sorted top a[:k], which
+ * holds elements that came earlier in the original order, so that a[:k]
+ * is the top of both.
+ */
+func mergeTopK[T any](a []T, k int, lt LessFunc[T]) {
+	if len(a) == k {
+		return
+	}
+	Slice(a[k:], lt)
+	MergeAdjacent(a, k, lt)
+}
+
+// TopK accumulates the k first elements, in sorted order, of a stream of
+// elements that may be too long to hold in memory.  Equal elements are
+// ordered by the time they were added, so the result matches the first k
+// elements of a stable sort of the whole stream.
+//
+// Like SortTopK, it collects candidate elements into a batch that is
+// sorted and merged into the current top when full, and holds at most
+// 2*k elements.
+type TopK[T any] struct {
+	k   int
+	lt  LessFunc[T]
+	top int // Length of the sorted top at the start of buf
+	buf []T
+}
+
+// NewTopK returns a TopK that keeps the k first elements according to lt.
+func NewTopK[T any](k int, lt LessFunc[T]) *TopK[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &TopK[T]{
+		k:   k,
+		lt:  lt,
+		buf: make([]T, 0, 2*k)}
+}
+
+// Add adds v to the stream.
+func (t *TopK[T]) Add(v T) {
+	if t.top == t.k && (t.k == 0 || !t.lt(v, t.buf[t.k-1])) {
+		return // Cannot make it into the top
+	}
+
+	t.buf = append(t.buf, v)
+	if len(t.buf) == 2*t.k {
+		t.flush()
+	}
+}
+
+// Result returns the k first elements added so far, in sorted order, or
+// all of them if fewer than k were added.  The returned slice is a copy.
+func (t *TopK[T]) Result() []T {
+	t.flush()
+	res := make([]T, t.top)
+	copy(res, t.buf[:t.top])
+	return res
+}
+
+func (t *TopK[T]) flush() {
+	if t.top == len(t.buf) {
+		return
+	}
+
+	Slice(t.buf[t.top:], t.lt)
+	MergeAdjacent(t.buf, t.top, t.lt)
+
+	t.top = min(len(t.buf), t.k)
+	clear(t.buf[t.top:])
+	t.buf = t.buf[:t.top]
+}
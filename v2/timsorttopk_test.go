@@ -0,0 +1,63 @@
+package timsort
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortTopK(t *testing.T) {
+	for _, size := range []int{0, 1, 10, 1000, 100 * 1024} {
+		for _, k := range []int{-1, 0, 1, 7, 100, size / 2, size} {
+			a := makeRandomVals(size)
+			want := slices.Clone(a)
+			Slice(want, valKeyLessThan)
+
+			SortTopK(a, k, valKeyLessThan)
+
+			n := min(max(k, 0), size)
+			if !slices.Equal(a[:n], want[:n]) {
+				t.Fatalf("size=%d k=%d: prefix differs from full sort", size, k)
+			}
+			Slice(a, valKeyOrderLessThan)
+			if !slices.Equal(a, want) {
+				t.Fatalf("size=%d k=%d: elements lost", size, k)
+			}
+		}
+	}
+}
+
+func TestSortTopKCompares(t *testing.T) {
+	size := 1024 * 1024
+	a := makeRandomArrayI(size)
+	for i := range a {
+		a[i] = a[i]*size + i // distinct keys
+	}
+
+	compares := 0
+	SortTopK(a, 100, func(x, y int) bool {
+		compares++
+		return x < y
+	})
+	if compares > 2*size {
+		t.Errorf("%d compares for top 100 of %d", compares, size)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	for _, size := range []int{0, 5, 1000, 100 * 1024} {
+		for _, k := range []int{0, 1, 10, 100} {
+			a := makeRandomVals(size)
+			top := NewTopK(k, valKeyLessThan)
+			for _, v := range a {
+				top.Add(v)
+			}
+
+			want := slices.Clone(a)
+			Slice(want, valKeyLessThan)
+			want = want[:min(k, size)]
+			if got := top.Result(); !slices.Equal(got, want) {
+				t.Fatalf("size=%d k=%d:\ngot  %v\nwant %v", size, k, got, want)
+			}
+		}
+	}
+}